@@ -0,0 +1,154 @@
+package nesgress
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// accessibleStatusInterval is the minimum time between announcements of what a running operation is doing.
+const accessibleStatusInterval = 10 * time.Second
+
+// accessibleGlyphs spell out the outcome of accomplishments and the causes of errors,
+// and leave out the tree connectors, which screen readers would read out one by one.
+var accessibleGlyphs = glyphSet{
+	spinner:  asciiGlyphs.spinner,
+	success:  "Done:",
+	failure:  "Failed:",
+	cause:    "Caused by: ",
+	ellipsis: asciiGlyphs.ellipsis,
+}
+
+// durationUnits are the units durations are spelled out in, largest first.
+var durationUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+	{"second", time.Second},
+}
+
+// accessibleFromEnvironment reports whether the ACCESSIBLE environment variable asks for accessible mode.
+// Values such as "0" and "false" turn it off, and any other non-empty value turns it on.
+func accessibleFromEnvironment() bool {
+	value := os.Getenv("ACCESSIBLE")
+	if value == "" {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(value)
+
+	return err != nil || enabled
+}
+
+// announceStart announces an operation that started, along with the operation it's part of.
+func (p *ProgressDisplay) announceStart(operation *ProgressOperation) error {
+	parts := p.contextParts(operation)
+
+	sentence := "Started " + parts[len(parts)-1]
+	if len(parts) > 1 {
+		sentence += ", part of " + parts[len(parts)-2]
+	}
+
+	return p.writeLine(p.output, sentence+".")
+}
+
+// announceCompletion announces the outcome of a completed operation, followed by its error if it failed.
+func (p *ProgressDisplay) announceCompletion(outcome operationOutcome) error {
+	var sentence string
+
+	if outcome.success {
+		sentence = "Finished " + outcome.message + " successfully"
+		if outcome.duration > durationDisplayThreshold {
+			sentence += " in " + spokenDuration(outcome.duration)
+		}
+	} else {
+		sentence = "Failed " + outcome.message
+		if outcome.duration > durationDisplayThreshold {
+			sentence += " after " + spokenDuration(outcome.duration)
+		}
+	}
+
+	sentence += "."
+
+	if outcome.err != nil {
+		sentence += "\n" + p.formatError(outcome.err, "")
+	}
+
+	output := p.resultOutput
+	if !outcome.success {
+		output = p.errorOutput
+	}
+
+	return p.writeLine(output, sentence)
+}
+
+// announceUpdate announces the new message of an operation, unless something was announced too recently.
+// Throttled messages are picked up by the next status announcement.
+func (p *ProgressDisplay) announceUpdate(message string) error {
+	p.renderMutex.Lock()
+	defer p.renderMutex.Unlock()
+
+	if time.Since(p.lastAnnouncement) < accessibleStatusInterval {
+		return nil
+	}
+
+	p.lastAnnouncement = time.Now()
+
+	_, err := fmt.Fprintf(p.output, "Now %s.\n", message)
+
+	return err
+}
+
+// announceStatus repeats what a long operation is doing, unless something was announced too recently.
+// It takes the place of spinner frames in accessible mode.
+// Note: This method assumes the caller holds renderMutex.
+func (p *ProgressDisplay) announceStatus(operation *ProgressOperation) {
+	if time.Since(p.lastAnnouncement) < accessibleStatusInterval {
+		return
+	}
+
+	p.lastAnnouncement = time.Now()
+
+	parts := p.contextParts(operation)
+	elapsed := spokenDuration(time.Since(operation.StartTime))
+
+	// The live status is styled for the spinner, but screen readers would read out its escape sequences
+	status := ansi.Strip(p.liveStatus(operation))
+
+	_, _ = fmt.Fprintf(p.output, "Still %s, %s so far%s.\n", parts[len(parts)-1], elapsed, status)
+}
+
+// spokenDuration spells out a duration for screen readers, e.g. "1 minute 12 seconds".
+func spokenDuration(duration time.Duration) string {
+	if duration < time.Second {
+		return pluralize(int(duration.Round(durationRoundPrecision).Milliseconds()), "millisecond")
+	}
+
+	duration = duration.Round(time.Second)
+
+	var parts []string
+
+	for _, unit := range durationUnits {
+		if count := int(duration / unit.size); count > 0 {
+			parts = append(parts, pluralize(count, unit.name))
+			duration -= time.Duration(count) * unit.size
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// pluralize prefixes a unit with its count, in plural unless the count is one.
+func pluralize(count int, unit string) string {
+	if count == 1 {
+		return "1 " + unit
+	}
+
+	return fmt.Sprintf("%d %ss", count, unit)
+}
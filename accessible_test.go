@@ -0,0 +1,109 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_Accessible_WithOperations_AnnouncesSentences(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithAccessible())
+
+	_ = display.Start("Deploying")
+	_ = display.Start("Uploading artifacts")
+	_ = display.Fail("Upload failed", fmt.Errorf("upload: %w", errors.New("connection refused")))
+	_ = display.Finish("Deployed")
+
+	require.NoError(t, display.Close())
+
+	output := display.GetOutputSafely()
+	require.Contains(t, output, "Started Deploying.\n")
+	require.Contains(t, output, "Started Uploading artifacts, part of Deploying.\n")
+	require.Contains(t, output, "Failed Uploading artifacts.\n")
+	require.Contains(t, output, "Error: upload\n  Caused by: connection refused\n")
+	require.Contains(t, output, "Finished Deploying successfully.\n")
+}
+
+func Test_Accessible_WithRunningOperation_NeverOverwritesLines(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithAccessible())
+
+	_ = display.Start("Installing")
+	time.Sleep(300 * time.Millisecond)
+	_ = display.Pause()
+	_ = display.Resume()
+	_ = display.Finish("Installed")
+
+	require.NoError(t, display.Close())
+
+	output := display.GetOutputSafely()
+	require.NotContains(t, output, "\r")
+	require.NotContains(t, output, "\033")
+	require.Regexp(t, `Finished Installing successfully in \d+ milliseconds\.\n`, output)
+}
+
+func Test_Accessible_WithRapidUpdates_ThrottlesAnnouncements(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithAccessible())
+
+	_ = display.Start("Installing")
+	_ = display.Update("Installing 1/3")
+	_ = display.Update("Installing 2/3")
+	_ = display.Update("Installing 3/3")
+	_ = display.Finish("Installed")
+
+	require.NoError(t, display.Close())
+
+	output := display.GetOutputSafely()
+	require.NotContains(t, output, "Now Installing")
+	require.Contains(t, output, "Finished Installing 3/3 successfully.\n")
+}
+
+func Test_Accessible_WithAccomplishment_SpellsOutOutcome(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithAccessible())
+
+	_ = display.StartPersistent("Deploying")
+	_ = display.LogAccomplishment("Built container")
+	_ = display.FinishPersistent("Deployed")
+
+	require.NoError(t, display.Close())
+	require.Contains(t, display.GetOutputSafely(), "Done: Built container\n")
+}
+
+func Test_Accessible_WithEnvironmentVariable_IsEnabled(t *testing.T) {
+	t.Setenv("ACCESSIBLE", "1")
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Installing")
+	_ = display.Finish("Installed")
+
+	require.NoError(t, display.Close())
+	require.Contains(t, display.GetOutputSafely(), "Started Installing.\n")
+}
+
+func Test_Accessible_WithFalseEnvironmentVariable_IsDisabled(t *testing.T) {
+	t.Setenv("ACCESSIBLE", "false")
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Installing")
+	_ = display.Finish("Installed")
+
+	require.NoError(t, display.Close())
+	require.NotContains(t, display.GetOutputSafely(), "Started Installing.")
+}
@@ -0,0 +1,116 @@
+package nesgress
+
+import (
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Mode is a kind of reporter Auto picks.
+type Mode string
+
+// Modes of Auto.
+const (
+	ModeTTY      Mode = "tty"      // the interactive ProgressDisplay
+	ModePlain    Mode = "plain"    // a LineReporter in PlainDialect
+	ModeJSON     Mode = "json"     // a LineReporter in JSONDialect
+	ModeGitHub   Mode = "github"   // a LineReporter in GitHubDialect
+	ModeGitLab   Mode = "gitlab"   // a LineReporter in GitLabDialect
+	ModeTeamCity Mode = "teamcity" // a LineReporter in TeamCityDialect
+	ModeAzure    Mode = "azure"    // a LineReporter in AzureDialect
+	ModeNone     Mode = "none"     // a NoopProgressDisplay
+)
+
+// modeVariable is the environment variable overriding the mode Auto detects.
+const modeVariable = "NESGRESS_MODE"
+
+// modes are the known modes, which NESGRESS_MODE may name.
+var modes = []Mode{ModeTTY, ModePlain, ModeJSON, ModeGitHub, ModeGitLab, ModeTeamCity, ModeAzure, ModeNone}
+
+// AutoOption configures Auto.
+type AutoOption func(*autoSettings)
+
+// autoSettings are the settings of Auto.
+type autoSettings struct {
+	mode           Mode     // kind of reporter to create, detected if empty
+	displayOptions []Option // options of the ProgressDisplay, if it's picked
+}
+
+// WithMode makes Auto create the given kind of reporter, regardless of the environment.
+func WithMode(mode Mode) AutoOption {
+	return func(s *autoSettings) {
+		s.mode = mode
+	}
+}
+
+// WithDisplayOptions configures the ProgressDisplay Auto creates with the given options, if it picks one.
+func WithDisplayOptions(opts ...Option) AutoOption {
+	return func(s *autoSettings) {
+		s.displayOptions = append(s.displayOptions, opts...)
+	}
+}
+
+// Auto creates the reporter suiting the environment it writes to:
+//   - the dialect of the CI system, when GitHub Actions, GitLab CI, TeamCity or Azure Pipelines run it
+//   - a plain LineReporter when the writer isn't a terminal, or TERM is dumb
+//   - the interactive ProgressDisplay otherwise
+//
+// The NESGRESS_MODE environment variable overrides the detection with one of the modes, e.g. NESGRESS_MODE=json,
+// and WithMode overrides both, e.g. with ModeNone for a quiet flag.
+// WithDisplayOptions configures the ProgressDisplay, if it's picked.
+func Auto(w io.Writer, opts ...AutoOption) ProgressReporter {
+	if w == nil {
+		w = os.Stdout
+	}
+
+	var settings autoSettings
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	switch pickMode(w, settings.mode) {
+	case ModeNone:
+		return NewNoopProgressDisplay()
+	case ModePlain:
+		return NewLineReporter(w, PlainDialect())
+	case ModeJSON:
+		return NewLineReporter(w, JSONDialect())
+	case ModeGitHub:
+		return NewLineReporter(w, GitHubDialect())
+	case ModeGitLab:
+		return NewLineReporter(w, GitLabDialect())
+	case ModeTeamCity:
+		return NewLineReporter(w, TeamCityDialect())
+	case ModeAzure:
+		return NewLineReporter(w, AzureDialect())
+	default: // ModeTTY
+		return NewProgressDisplay(w, settings.displayOptions...)
+	}
+}
+
+// pickMode returns the given mode or the one asked for by the environment, or detects it.
+func pickMode(w io.Writer, mode Mode) Mode {
+	if mode != "" {
+		return mode
+	}
+
+	if mode = Mode(strings.ToLower(os.Getenv(modeVariable))); slices.Contains(modes, mode) {
+		return mode
+	}
+
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return ModeGitHub
+	case os.Getenv("GITLAB_CI") == "true":
+		return ModeGitLab
+	case os.Getenv("TEAMCITY_VERSION") != "":
+		return ModeTeamCity
+	case strings.EqualFold(os.Getenv("TF_BUILD"), "true"):
+		return ModeAzure
+	case os.Getenv("TERM") == "dumb" || !isTerminal(w):
+		return ModePlain
+	default:
+		return ModeTTY
+	}
+}
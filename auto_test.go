@@ -0,0 +1,130 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+// clearModeEnvironment unsets the environment Auto detects its mode from, such as the CI running the tests.
+func clearModeEnvironment(t *testing.T) {
+	t.Helper()
+
+	for _, variable := range []string{"NESGRESS_MODE", "GITHUB_ACTIONS", "GITLAB_CI", "TEAMCITY_VERSION", "TF_BUILD", "TERM"} {
+		t.Setenv(variable, "")
+	}
+}
+
+func Test_Auto_WithNonTerminalWriter_WritesPlainLines(t *testing.T) {
+	clearModeEnvironment(t)
+
+	var buf bytes.Buffer
+	reporter := nesgress.Auto(&buf)
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Finish("Installed")
+
+	require.IsType(t, &nesgress.LineReporter{}, reporter)
+	require.Equal(t, "Installing...\n[ok] Installing\n", buf.String())
+}
+
+func Test_Auto_WithCIEnvironment_WritesDialectOfCI(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable string
+		value    string
+		expected string
+	}{
+		{name: "GitHub Actions", variable: "GITHUB_ACTIONS", value: "true", expected: "::group::Installing\n"},
+		{name: "GitLab CI", variable: "GITLAB_CI", value: "true", expected: "section_start:"},
+		{
+			name:     "TeamCity",
+			variable: "TEAMCITY_VERSION",
+			value:    "2024.12 (build 174331)",
+			expected: "##teamcity[blockOpened name='Installing']\n",
+		},
+		{name: "Azure Pipelines", variable: "TF_BUILD", value: "True", expected: "##[group]Installing\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearModeEnvironment(t)
+			t.Setenv(tt.variable, tt.value)
+
+			var buf bytes.Buffer
+			reporter := nesgress.Auto(&buf)
+
+			_ = reporter.Start("Installing")
+
+			require.Contains(t, buf.String(), tt.expected)
+		})
+	}
+}
+
+func Test_Auto_WithModeVariable_OverridesDetection(t *testing.T) {
+	clearModeEnvironment(t)
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("NESGRESS_MODE", "json")
+
+	var buf bytes.Buffer
+	reporter := nesgress.Auto(&buf)
+
+	_ = reporter.Start("Installing")
+
+	require.Contains(t, buf.String(), `"type":"start"`)
+}
+
+func Test_Auto_WithUnknownModeVariable_DetectsMode(t *testing.T) {
+	clearModeEnvironment(t)
+	t.Setenv("NESGRESS_MODE", "fancy")
+
+	var buf bytes.Buffer
+	reporter := nesgress.Auto(&buf)
+
+	require.IsType(t, &nesgress.LineReporter{}, reporter)
+}
+
+func Test_Auto_WithTTYModeVariable_CreatesProgressDisplay(t *testing.T) {
+	clearModeEnvironment(t)
+	t.Setenv("NESGRESS_MODE", "tty")
+
+	var buf bytes.Buffer
+	reporter := nesgress.Auto(&buf)
+
+	require.IsType(t, &nesgress.ProgressDisplay{}, reporter)
+	require.NoError(t, reporter.Close())
+}
+
+func Test_Auto_WithModeOption_OverridesEnvironment(t *testing.T) {
+	clearModeEnvironment(t)
+	t.Setenv("NESGRESS_MODE", "json")
+
+	var buf bytes.Buffer
+	reporter := nesgress.Auto(&buf, nesgress.WithMode(nesgress.ModeNone))
+
+	_ = reporter.Start("Installing")
+
+	require.IsType(t, &nesgress.NoopProgressDisplay{}, reporter)
+	require.Empty(t, buf.String())
+}
+
+func Test_Auto_WithDisplayOptions_ConfiguresProgressDisplay(t *testing.T) {
+	clearModeEnvironment(t)
+
+	var buf bytes.Buffer
+	reporter := nesgress.Auto(&buf,
+		nesgress.WithMode(nesgress.ModeTTY),
+		nesgress.WithDisplayOptions(nesgress.WithTreeOutput()),
+	)
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading")
+	_ = reporter.Finish("Downloaded")
+	_ = reporter.Finish("Installed")
+
+	require.NoError(t, reporter.Close())
+	require.Contains(t, buf.String(), "└─ ✓ Downloading")
+}
@@ -0,0 +1,30 @@
+package nesgress
+
+import (
+	"strings"
+)
+
+// Escaping of Azure Pipelines logging commands, and flattening of group titles onto a line.
+var (
+	azureDataEscaper    = strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A")
+	azureTitleFlattener = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+)
+
+// AzureDialect writes progress like PlainDialect, wrapping every top-level operation in a collapsible group
+// of the Azure Pipelines log, and reporting failures as errors of the pipeline run.
+func AzureDialect() Dialect {
+	return groupDialect{
+		begin: func(title string) string {
+			return "##[group]" + azureTitleFlattener.Replace(title)
+		},
+		end:     "##[endgroup]",
+		failure: azureIssueCommand,
+	}
+}
+
+// azureIssueCommand returns the logging command reporting a failed operation as an error.
+func azureIssueCommand(event Event) string {
+	message := strings.Join(append(operationPath(event), failureMessage(event.Operation)), contextSeparator)
+
+	return "##vso[task.logissue type=error]" + azureDataEscaper.Replace(message)
+}
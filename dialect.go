@@ -0,0 +1,134 @@
+package nesgress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// plainIndentUnit is the indentation added for each level of nesting in plain text.
+const plainIndentUnit = "  "
+
+// plainDialect writes progress as plain text, indented by nesting.
+type plainDialect struct{}
+
+// PlainDialect writes progress as plain text without any control sequences or styling,
+// indented by nesting and with ASCII glyphs, e.g. for pipes, log files and dumb terminals:
+//
+//	Installing packages...
+//	  Downloading zlib...
+//	  [ok] Downloading zlib (took 1.2s)
+//	[ok] Installing packages (took 3s)
+func PlainDialect() Dialect {
+	return plainDialect{}
+}
+
+// WriteEvent writes the plain text line of an event.
+func (plainDialect) WriteEvent(w io.Writer, event Event) error {
+	return writeLines(w, plainText(event))
+}
+
+// plainText formats an event as plain text, or returns an empty string if it has no text.
+func plainText(event Event) string {
+	level := 0
+	if event.Operation != nil {
+		level = event.Operation.Level
+	}
+
+	indent := strings.Repeat(plainIndentUnit, level)
+
+	switch event.Type {
+	case EventStart, EventUpdate:
+		return indent + event.Operation.Message + "..."
+	case EventFinish:
+		return indent + plainOutcome(event.Operation, true)
+	case EventFail:
+		text := indent + plainOutcome(event.Operation, false)
+		if event.Operation.Error != nil {
+			text += "\n" + renderError(event.Operation.Error, indent+errorIndent, asciiGlyphs.cause, false)
+		}
+
+		return text
+	case EventCancel:
+		return indent + "[canceled] " + event.Operation.Message
+	case EventAccomplishment:
+		return indent + plainIndentUnit + asciiGlyphs.success + " " + event.Message
+	case EventLog:
+		return indent + plainIndentUnit + event.Message
+	default:
+		return ""
+	}
+}
+
+// plainOutcome formats the outcome of a completed operation, including its duration when meaningful.
+func plainOutcome(operation *OperationSnapshot, success bool) string {
+	if success {
+		outcome := asciiGlyphs.success + " " + operation.Message
+		if operation.Duration > durationDisplayThreshold {
+			outcome += fmt.Sprintf(" (took %v)", operation.Duration.Round(durationRoundPrecision))
+		}
+
+		return outcome
+	}
+
+	outcome := asciiGlyphs.failure + " " + operation.Message
+	if operation.Duration > durationDisplayThreshold {
+		outcome += fmt.Sprintf(" (failed after %v)", operation.Duration.Round(durationRoundPrecision))
+	}
+
+	return outcome
+}
+
+// jsonDialect writes progress as JSON lines.
+type jsonDialect struct{}
+
+// jsonEvent is the JSON form of an event.
+type jsonEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Message string    `json:"message,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	// Operation is the path of the operation, outermost first
+	Operation  []string `json:"operation,omitempty"`
+	DurationMs int64    `json:"durationMs,omitempty"`
+}
+
+// JSONDialect writes every event as a JSON object on a line of its own, for machines to follow along:
+//
+//	{"time":"2025-01-02T15:04:05Z","type":"finish","operation":["Installing"],"durationMs":3000}
+func JSONDialect() Dialect {
+	return jsonDialect{}
+}
+
+// WriteEvent writes an event as a line of JSON.
+func (jsonDialect) WriteEvent(w io.Writer, event Event) error {
+	record := jsonEvent{
+		Time:    event.Time,
+		Type:    event.Type.String(),
+		Message: event.Message,
+	}
+
+	if event.Operation != nil {
+		record.Operation = operationPath(event)
+		record.DurationMs = event.Operation.Duration.Milliseconds()
+
+		if event.Operation.Error != nil {
+			record.Error = event.Operation.Error.Error()
+		}
+	}
+
+	return json.NewEncoder(w).Encode(record)
+}
+
+// operationPath returns the messages of an event's operation and its ancestors, outermost first.
+func operationPath(event Event) []string {
+	path := make([]string, 0, len(event.Ancestors)+1)
+
+	for _, ancestor := range event.Ancestors {
+		path = append(path, ancestor.Message)
+	}
+
+	return append(path, event.Operation.Message)
+}
@@ -0,0 +1,326 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_JSONDialect_WithNestedOperations_WritesEventPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.JSONDialect())
+
+	_ = reporter.Start("Deploying")
+	_ = reporter.Start("Uploading")
+	_ = reporter.Fail("Upload failed", errors.New("connection refused"))
+	_ = reporter.Finish("Deployed")
+
+	type record struct {
+		Type      string   `json:"type"`
+		Error     string   `json:"error"`
+		Operation []string `json:"operation"`
+	}
+
+	var records []record
+
+	for line := range strings.Lines(buf.String()) {
+		var r record
+		require.NoError(t, json.Unmarshal([]byte(line), &r))
+
+		records = append(records, r)
+	}
+
+	require.Equal(t, []record{
+		{Type: "start", Operation: []string{"Deploying"}},
+		{Type: "start", Operation: []string{"Deploying", "Uploading"}},
+		{Type: "fail", Operation: []string{"Deploying", "Uploading"}, Error: "connection refused"},
+		{Type: "finish", Operation: []string{"Deploying"}},
+	}, records)
+}
+
+func Test_GitHubDialect_WithTopLevelOperation_WrapsItInGroup(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.GitHubDialect())
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading zlib")
+	_ = reporter.Finish("Downloaded zlib")
+	_ = reporter.Finish("Installed")
+
+	require.Equal(t,
+		"::group::Installing\n  Downloading zlib...\n  [ok] Downloading zlib\n::endgroup::\n[ok] Installing\n",
+		buf.String(),
+	)
+}
+
+func Test_GitHubDialect_WithFailure_AnnotatesEscapedError(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.GitHubDialect())
+
+	_ = reporter.Start("Deploying")
+	_ = reporter.Start("Uploading")
+	_ = reporter.Fail("Upload failed", errors.New("100% failed\nretry later"))
+
+	require.Contains(t, buf.String(), "::error title=Deploying%3A Uploading::100%25 failed%0Aretry later\n")
+}
+
+func Test_GitHubDialect_WhenCleared_EndsGroup(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.GitHubDialect())
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading zlib")
+
+	require.NoError(t, reporter.Close())
+	require.Equal(t,
+		"::group::Installing\n  Downloading zlib...\n  [canceled] Downloading zlib\n::endgroup::\n[canceled] Installing\n",
+		buf.String(),
+	)
+}
+
+func Test_GitLabDialect_WithTopLevelOperation_WrapsItInSection(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.GitLabDialect())
+
+	_ = reporter.Start("Installing Packages!")
+	_ = reporter.Finish("Installed")
+
+	output := buf.String()
+
+	require.Regexp(t, `^\x1b\[0Ksection_start:\d+:installing_packages_1\[collapsed=true\]\r\x1b\[0KInstalling Packages!\n`, output)
+	require.Regexp(t, `\x1b\[0Ksection_end:\d+:installing_packages_1\r\x1b\[0K\n\[ok\] Installing Packages!\n$`, output)
+}
+
+func Test_GitLabDialect_WithNestedSections_WrapsNestedOperations(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.GitLabDialect(nesgress.WithNestedSections()))
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading zlib")
+	_ = reporter.Finish("Downloaded zlib")
+	_ = reporter.Finish("Installed")
+
+	require.Regexp(t, strings.Join([]string{
+		`^\x1b\[0Ksection_start:\d+:installing_1\[collapsed=true\]\r\x1b\[0KInstalling\n`,
+		`\x1b\[0Ksection_start:\d+:downloading_zlib_2\[collapsed=true\]\r\x1b\[0K  Downloading zlib\n`,
+		`\x1b\[0Ksection_end:\d+:downloading_zlib_2\r\x1b\[0K\n`,
+		`  \[ok\] Downloading zlib\n`,
+		`\x1b\[0Ksection_end:\d+:installing_1\r\x1b\[0K\n`,
+		`\[ok\] Installing\n$`,
+	}, ""), buf.String())
+}
+
+func Test_GitLabDialect_WithExpandedSections_OmitsCollapsedOption(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.GitLabDialect(nesgress.WithExpandedSections()))
+
+	_ = reporter.Start("Installing")
+
+	require.Regexp(t, `^\x1b\[0Ksection_start:\d+:installing_1\r`, buf.String())
+}
+
+func Test_GitLabDialect_WithFailure_WritesDurationOfOperationAndError(t *testing.T) {
+	var buf bytes.Buffer
+	dialect := nesgress.GitLabDialect()
+
+	start := time.Unix(1735830245, 0)
+	operation := nesgress.OperationSnapshot{StartTime: start, Message: "Deploying"}
+
+	require.NoError(t, dialect.WriteEvent(&buf, nesgress.Event{
+		Type:      nesgress.EventStart,
+		Time:      start.Add(200 * time.Millisecond),
+		Operation: &operation,
+	}))
+
+	failed := operation
+	failed.Duration = 2600 * time.Millisecond
+	failed.Error = errors.New("registry unavailable")
+
+	require.NoError(t, dialect.WriteEvent(&buf, nesgress.Event{
+		Type:      nesgress.EventFail,
+		Time:      start.Add(2600 * time.Millisecond),
+		Operation: &failed,
+	}))
+
+	require.Equal(t,
+		"\x1b[0Ksection_start:1735830245:deploying_1[collapsed=true]\r\x1b[0KDeploying\n"+
+			"\x1b[0Ksection_end:1735830247:deploying_1\r\x1b[0K\n"+
+			"[FAIL] Deploying (failed after 2.6s)\n"+
+			"  Error: registry unavailable\n",
+		buf.String(),
+	)
+}
+
+func Test_GitLabDialect_WithFailure_RepeatsSectionExpanded(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.GitLabDialect())
+
+	_ = reporter.Start("Deploying")
+	_ = reporter.Start("Uploading")
+	_ = reporter.Fail("Upload failed", errors.New("connection refused"))
+	_ = reporter.Fail("Deploy failed", errors.New("upload failed"))
+
+	require.Regexp(t, strings.Join([]string{
+		`^\x1b\[0Ksection_start:\d+:deploying_1\[collapsed=true\]\r\x1b\[0KDeploying\n`,
+		`  Uploading\.\.\.\n`,
+		`  \[FAIL\] Uploading\n`,
+		`    Error: connection refused\n`,
+		`\x1b\[0Ksection_end:\d+:deploying_1\r\x1b\[0K\n`,
+		`\x1b\[0Ksection_start:\d+:deploying_1_failed\r\x1b\[0KDeploying failed\n`,
+		`  Uploading\.\.\.\n`,
+		`  \[FAIL\] Uploading\n`,
+		`    Error: connection refused\n`,
+		`\x1b\[0Ksection_end:\d+:deploying_1_failed\r\x1b\[0K\n`,
+		`\[FAIL\] Deploying\n`,
+		`  Error: upload failed\n$`,
+	}, ""), buf.String())
+}
+
+func Test_GitLabDialect_WithNestedFailure_RepeatsEnclosingSectionExpanded(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.GitLabDialect())
+
+	_ = reporter.Start("Deploying")
+	_ = reporter.Start("Uploading")
+	_ = reporter.Fail("Upload failed", errors.New("connection refused"))
+	_ = reporter.Finish("Deployed")
+
+	require.Regexp(t, strings.Join([]string{
+		`\x1b\[0Ksection_end:\d+:deploying_1\r\x1b\[0K\n`,
+		`\x1b\[0Ksection_start:\d+:deploying_1_failed\r\x1b\[0KDeploying had failures\n`,
+		`  Uploading\.\.\.\n`,
+		`  \[FAIL\] Uploading\n`,
+		`    Error: connection refused\n`,
+		`\x1b\[0Ksection_end:\d+:deploying_1_failed\r\x1b\[0K\n`,
+		`\[ok\] Deploying\n$`,
+	}, ""), buf.String())
+}
+
+func Test_GitLabDialect_WithSucceedingOperations_RepeatsNothing(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.GitLabDialect())
+
+	_ = reporter.Start("Deploying")
+	_ = reporter.Start("Uploading")
+	_ = reporter.Finish("Uploaded")
+	_ = reporter.Finish("Deployed")
+
+	require.NotContains(t, buf.String(), "_failed")
+}
+
+func Test_GitLabDialect_WhenCleared_EndsSections(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.GitLabDialect(nesgress.WithNestedSections()))
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading zlib")
+
+	require.NoError(t, reporter.Close())
+	require.Regexp(t, strings.Join([]string{
+		`\x1b\[0Ksection_end:\d+:downloading_zlib_2\r\x1b\[0K\n`,
+		`  \[canceled\] Downloading zlib\n`,
+		`\x1b\[0Ksection_end:\d+:installing_1\r\x1b\[0K\n`,
+		`\[canceled\] Installing\n$`,
+	}, ""), buf.String())
+}
+
+func Test_TeamCityDialect_WithNestedOperations_WrapsThemInBlocks(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.TeamCityDialect())
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading zlib")
+	_ = reporter.Update("Downloading zlib 1.3")
+	_ = reporter.Finish("Downloaded zlib")
+	_ = reporter.Finish("Installed")
+
+	require.Equal(t, strings.Join([]string{
+		"##teamcity[blockOpened name='Installing']",
+		"##teamcity[blockOpened name='Downloading zlib']",
+		"  Downloading zlib 1.3...",
+		"##teamcity[progressMessage 'Downloading zlib 1.3']",
+		"##teamcity[blockClosed name='Downloading zlib']",
+		"  [ok] Downloading zlib 1.3",
+		"##teamcity[blockClosed name='Installing']",
+		"[ok] Installing",
+		"",
+	}, "\n"), buf.String())
+}
+
+func Test_TeamCityDialect_WithFailure_ReportsEscapedBuildProblem(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.TeamCityDialect())
+
+	_ = reporter.Start("Deploying [staging]")
+	_ = reporter.Fail("Deploy failed", errors.New("can't reach|host\nretrying"))
+
+	require.Contains(t, buf.String(),
+		"##teamcity[blockClosed name='Deploying |[staging|]']\n"+
+			"##teamcity[buildProblem description='Deploying |[staging|]: can|'t reach||host|nretrying']\n"+
+			"[FAIL] Deploying [staging]\n",
+	)
+}
+
+func Test_TeamCityDialect_WhenCleared_ClosesBlocks(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.TeamCityDialect())
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading zlib")
+
+	require.NoError(t, reporter.Close())
+	require.Equal(t, strings.Join([]string{
+		"##teamcity[blockOpened name='Installing']",
+		"##teamcity[blockOpened name='Downloading zlib']",
+		"##teamcity[blockClosed name='Downloading zlib']",
+		"  [canceled] Downloading zlib",
+		"##teamcity[blockClosed name='Installing']",
+		"[canceled] Installing",
+		"",
+	}, "\n"), buf.String())
+}
+
+func Test_AzureDialect_WithTopLevelOperation_WrapsItInGroup(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.AzureDialect())
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading zlib")
+	_ = reporter.Finish("Downloaded zlib")
+	_ = reporter.Finish("Installed")
+
+	require.Equal(t,
+		"##[group]Installing\n  Downloading zlib...\n  [ok] Downloading zlib\n##[endgroup]\n[ok] Installing\n",
+		buf.String(),
+	)
+}
+
+func Test_AzureDialect_WhenCleared_EndsGroup(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.AzureDialect())
+
+	_ = reporter.Start("Installing")
+
+	require.NoError(t, reporter.Close())
+	require.Equal(t, "##[group]Installing\n##[endgroup]\n[canceled] Installing\n", buf.String())
+}
+
+func Test_AzureDialect_WithFailure_LogsEscapedIssue(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.AzureDialect())
+
+	_ = reporter.Start("Deploying")
+	_ = reporter.Start("Uploading")
+	_ = reporter.Fail("Upload failed", errors.New("100% failed\nretry later"))
+
+	require.Contains(t, buf.String(),
+		"##vso[task.logissue type=error]Deploying: Uploading: 100%AZP25 failed%0Aretry later\n",
+	)
+}
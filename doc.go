@@ -7,10 +7,11 @@
 //
 //   - Hierarchical progress display with parent-child relationships
 //   - Thread-safe concurrent operation support
-//   - Spinner animations using charmbracelet/huh
+//   - Spinner animations drawn by a single render loop
 //   - Success/failure indicators with timing information
 //   - Persistent mode for long-running operations with accomplishments
 //   - Pause/resume support for interactive prompts
+//   - Optional tree output that preserves the operation hierarchy
 //
 // # Basic Usage
 //
@@ -31,6 +32,15 @@
 //	    display.Finish("Compiled")
 //	display.Finish("Environment ready")
 //
+// # Tree Output
+//
+// To keep the structure of a nested run visible once it completes:
+//
+//	display := nesgress.NewProgressDisplay(os.Stdout, nesgress.WithTreeOutput())
+//
+// Completions of nested operations are printed under their parent with tree connectors
+// once the top-level operation ends.
+//
 // # Persistent Mode
 //
 // For long-running operations where you want to show intermediate accomplishments:
@@ -0,0 +1,57 @@
+package nesgress
+
+import (
+	"fmt"
+	"time"
+)
+
+// ElapsedFormatter formats the running time shown next to an active operation.
+type ElapsedFormatter func(elapsed time.Duration) string
+
+// formatElapsed is the default ElapsedFormatter, e.g. "(1m12s)".
+func formatElapsed(elapsed time.Duration) string {
+	return fmt.Sprintf("(%v)", roundLiveDuration(elapsed))
+}
+
+// roundLiveDuration rounds a duration shown on the live line, which is redrawn too often for sub-second noise.
+func roundLiveDuration(duration time.Duration) time.Duration {
+	if duration >= time.Second {
+		return duration.Truncate(time.Second)
+	}
+
+	return duration.Round(durationRoundPrecision)
+}
+
+// elapsedLabel returns the formatted running time of an operation,
+// or an empty string while it is disabled or below its threshold.
+func (p *ProgressDisplay) elapsedLabel(operation *ProgressOperation) string {
+	if !p.showElapsed {
+		return ""
+	}
+
+	elapsed := time.Since(operation.StartTime)
+	if elapsed < p.elapsedThreshold {
+		return ""
+	}
+
+	format := p.elapsedFormat
+	if format == nil {
+		format = formatElapsed
+	}
+
+	return format(elapsed)
+}
+
+// timedContextParts returns the parts of the contextual message of an operation,
+// with the running time of every operation in its ancestry.
+func (p *ProgressDisplay) timedContextParts(operation *ProgressOperation) []string {
+	parts := p.contextParts(operation)
+
+	for current, i := operation, len(parts)-1; current != nil; current, i = current.parent, i-1 {
+		if label := p.elapsedLabel(current); label != "" {
+			parts[i] += " " + label
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,87 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_ElapsedTime_OverThreshold_ShowsRunningTimer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithElapsedTime(50*time.Millisecond))
+
+	_ = display.Start("Compiling")
+	time.Sleep(200 * time.Millisecond)
+
+	require.Regexp(t, `Compiling \(\d+ms\)`, display.GetOutputSafely())
+
+	_ = display.Finish("Compiling")
+}
+
+func Test_ElapsedTime_BelowThreshold_StaysHidden(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithElapsedTime(time.Minute))
+
+	_ = display.Start("Compiling")
+	time.Sleep(100 * time.Millisecond)
+
+	require.NotRegexp(t, `Compiling \(`, display.GetOutputSafely())
+
+	_ = display.Finish("Compiling")
+}
+
+func Test_ElapsedTime_WithCustomFormat_UsesFormatter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf,
+		nesgress.WithElapsedTime(0),
+		nesgress.WithElapsedFormat(func(elapsed time.Duration) string {
+			return fmt.Sprintf("[%ds]", int(elapsed.Seconds()))
+		}),
+	)
+
+	_ = display.Start("Compiling")
+	time.Sleep(100 * time.Millisecond)
+	_ = display.Finish("Compiling")
+
+	require.Contains(t, display.GetOutputSafely(), "Compiling [0s]")
+}
+
+func Test_AncestorElapsedTime_WithNestedOperations_ShowsTimerForEachLevel(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf,
+		nesgress.WithAncestorElapsedTime(),
+		nesgress.WithElapsedFormat(func(time.Duration) string {
+			return "[t]"
+		}),
+	)
+
+	_ = display.Start("Deploying")
+	_ = display.Start("Uploading")
+	time.Sleep(100 * time.Millisecond)
+
+	require.Contains(t, display.GetOutputSafely(), "Deploying [t]: Uploading [t]")
+
+	_ = display.Finish("Uploading")
+	_ = display.Finish("Deploying")
+}
@@ -0,0 +1,128 @@
+package nesgress
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Markers introducing each line of a rendered error.
+const (
+	errorMarker = "Error: "
+	joinMarker  = "- "
+	errorIndent = "  " // Added for every level of causes
+)
+
+// formatError renders err as an indented list, following %w chains and errors.Join branches.
+// Every line starts with prefix, and the result has no trailing newline.
+func (p *ProgressDisplay) formatError(err error, prefix string) string {
+	return renderError(err, prefix, p.glyphs.cause, p.errorDetails)
+}
+
+// renderError renders err like formatError, introducing causes with the given marker,
+// and adding the %+v details of the error if asked to.
+func renderError(err error, prefix, causeMarker string, withDetails bool) string {
+	var builder strings.Builder
+
+	writeErrorNode(&builder, err, prefix, errorMarker, causeMarker)
+
+	if withDetails {
+		// Errors implementing fmt.Formatter may carry stack traces or other details behind %+v
+		if details := fmt.Sprintf("%+v", err); details != err.Error() {
+			builder.WriteString(prefix + "Details:\n")
+
+			for line := range strings.SplitSeq(strings.TrimRight(details, "\n"), "\n") {
+				builder.WriteString(prefix + errorIndent + line + "\n")
+			}
+		}
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// writeErrorNode writes a single layer of an error and descends into its causes.
+func writeErrorNode(builder *strings.Builder, err error, indent, marker, causeMarker string) {
+	message, causes := splitError(err)
+
+	// Wrappers that add no text of their own are skipped
+	if message == "" && len(causes) == 1 {
+		writeErrorNode(builder, causes[0], indent, marker, causeMarker)
+		return
+	}
+
+	if message == "" && len(causes) > 1 {
+		message = fmt.Sprintf("%d errors", len(causes))
+	} else if message == "" {
+		message = "(no message)"
+	}
+
+	// Continuation lines of multi-line messages hang under the first line's text
+	hangingIndent := indent + strings.Repeat(" ", utf8.RuneCountInString(marker))
+
+	for i, line := range strings.Split(message, "\n") {
+		if i == 0 {
+			builder.WriteString(indent + marker + line + "\n")
+		} else {
+			builder.WriteString(hangingIndent + line + "\n")
+		}
+	}
+
+	childMarker := causeMarker
+	if len(causes) > 1 {
+		childMarker = joinMarker
+	}
+
+	for _, cause := range causes {
+		writeErrorNode(builder, cause, indent+errorIndent, childMarker, causeMarker)
+	}
+}
+
+// splitError separates the text an error adds itself from the errors it wraps.
+// When the wrapped text can't be separated, the full message is returned without causes,
+// so nothing is printed twice.
+func splitError(err error) (string, []error) {
+	message := err.Error()
+
+	var causes []error
+
+	switch wrapper := err.(type) { //nolint:errorlint // Inspecting this layer only, not the chain
+	case interface{ Unwrap() []error }:
+		for _, cause := range wrapper.Unwrap() {
+			if cause != nil {
+				causes = append(causes, cause)
+			}
+		}
+	default:
+		if cause := errors.Unwrap(err); cause != nil {
+			causes = append(causes, cause)
+		}
+	}
+
+	if len(causes) == 0 {
+		return message, nil
+	}
+
+	causeMessages := make([]string, 0, len(causes))
+	for _, cause := range causes {
+		causeMessages = append(causeMessages, cause.Error())
+	}
+
+	// errors.Join separates its branches with newlines
+	wrapped := strings.Join(causeMessages, "\n")
+
+	if message == wrapped {
+		return "", causes
+	}
+
+	if own, ok := strings.CutSuffix(message, ": "+wrapped); ok {
+		return own, causes
+	}
+
+	if len(causes) == 1 && !strings.Contains(message, wrapped) {
+		// The wrapper describes itself without repeating its cause
+		return message, causes
+	}
+
+	return message, nil
+}
@@ -0,0 +1,121 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+// tracedError mimics errors that expose extra details through %+v, such as stack traces.
+type tracedError struct {
+	message string
+}
+
+func (e *tracedError) Error() string {
+	return e.message
+}
+
+func (e *tracedError) Format(state fmt.State, verb rune) {
+	if verb == 'v' && state.Flag('+') {
+		_, _ = io.WriteString(state, e.message+"\nmain.deploy\n\tdeploy.go:42")
+		return
+	}
+
+	_, _ = io.WriteString(state, e.message)
+}
+
+// failWith runs a single failing operation and returns its rendered completion lines.
+func failWith(t *testing.T, err error, opts ...nesgress.Option) []string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, opts...)
+
+	require.NoError(t, display.Start("Deploying"))
+	require.NoError(t, display.Fail("Deploying", err))
+
+	return completionLines(display.GetOutputSafely())
+}
+
+func Test_FailureOutput_WithWrappedErrorChain_RendersEachLayerIndented(t *testing.T) {
+	root := errors.New("connection refused")
+	err := fmt.Errorf("deploy failed: %w", fmt.Errorf("upload artifacts: %w", root))
+
+	lines := failWith(t, err)
+
+	require.Equal(t, []string{
+		"✗ Deploying",
+		"  Error: deploy failed",
+		"    ↳ upload artifacts",
+		"      ↳ connection refused",
+	}, lines)
+}
+
+func Test_FailureOutput_WithJoinedErrors_RendersBranchesAsList(t *testing.T) {
+	err := fmt.Errorf("cleanup: %w", errors.Join(
+		errors.New("remove cache"),
+		fmt.Errorf("stop daemon: %w", errors.New("timeout")),
+	))
+
+	lines := failWith(t, err)
+
+	require.Equal(t, []string{
+		"✗ Deploying",
+		"  Error: cleanup",
+		"    ↳ 2 errors",
+		"      - remove cache",
+		"      - stop daemon",
+		"        ↳ timeout",
+	}, lines)
+}
+
+func Test_FailureOutput_WithMultiLineMessage_UsesHangingIndent(t *testing.T) {
+	lines := failWith(t, errors.New("invalid config\nline 3: unknown key"))
+
+	require.Equal(t, []string{
+		"✗ Deploying",
+		"  Error: invalid config",
+		"         line 3: unknown key",
+	}, lines)
+}
+
+func Test_FailureOutput_WithWrapperAddingNoText_SkipsWrapper(t *testing.T) {
+	err := fmt.Errorf("%w", errors.New("disk full"))
+
+	lines := failWith(t, err)
+
+	require.Equal(t, []string{"✗ Deploying", "  Error: disk full"}, lines)
+}
+
+func Test_FailureOutput_WithEmptyMessage_RendersPlaceholder(t *testing.T) {
+	lines := failWith(t, errors.New(""))
+
+	require.Equal(t, []string{"✗ Deploying", "  Error: (no message)"}, lines)
+}
+
+func Test_FailureOutput_WithErrorDetailsOption_RendersFormatterDetails(t *testing.T) {
+	err := &tracedError{message: "disk full"}
+
+	lines := failWith(t, err, nesgress.WithErrorDetails())
+
+	require.Equal(t, []string{
+		"✗ Deploying",
+		"  Error: disk full",
+		"  Details:",
+		"    disk full",
+		"    main.deploy",
+		"    \tdeploy.go:42",
+	}, lines)
+}
+
+func Test_FailureOutput_WithoutErrorDetailsOption_OmitsFormatterDetails(t *testing.T) {
+	lines := failWith(t, &tracedError{message: "disk full"})
+
+	require.Equal(t, []string{"✗ Deploying", "  Error: disk full"}, lines)
+}
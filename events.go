@@ -0,0 +1,248 @@
+package nesgress
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of a lifecycle event.
+type EventType int
+
+// Lifecycle event types.
+const (
+	EventStart EventType = iota + 1
+	EventUpdate
+	EventFinish
+	EventFail
+	EventAccomplishment
+	EventLog
+	EventPause
+	EventResume
+	// EventCancel reports an operation stopped by Clear or Close before it completed, innermost first
+	EventCancel
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventStart:
+		return "start"
+	case EventUpdate:
+		return "update"
+	case EventFinish:
+		return "finish"
+	case EventFail:
+		return "fail"
+	case EventAccomplishment:
+		return "accomplishment"
+	case EventLog:
+		return "log"
+	case EventPause:
+		return "pause"
+	case EventResume:
+		return "resume"
+	case EventCancel:
+		return "cancel"
+	default:
+		return "unknown"
+	}
+}
+
+// OperationSnapshot is a copy of an operation's state at the time of an event.
+type OperationSnapshot struct {
+	StartTime time.Time
+	// Error is the error a failed operation completed with
+	Error error
+	// Message is the operation's current message
+	Message string
+	// Key is the operation's stable key, see ProgressDisplay.SetKey
+	Key   string
+	Level int
+	// Duration is set once the operation completed
+	Duration   time.Duration
+	Persistent bool
+}
+
+// Event describes a change in a progress display.
+type Event struct {
+	Time time.Time
+	// Operation is the operation the event is about, nil when there is none,
+	// e.g. for accomplishments logged or pauses happening outside any operation
+	Operation *OperationSnapshot
+	// Message is the text of accomplishments and log lines
+	Message string
+	// Ancestors are the operations enclosing Operation, outermost first
+	Ancestors []OperationSnapshot
+	Type      EventType
+}
+
+// Subscribe registers an observer of the display's lifecycle events and returns a function removing it.
+//
+// Observers are called one event at a time, in order, from a dispatcher goroutine,
+// so a slow observer delays later events but never the display itself.
+// Close waits for pending events to be delivered, so observers must not call it.
+func (p *ProgressDisplay) Subscribe(observer func(Event)) (unsubscribe func()) {
+	return p.events.subscribe(observer)
+}
+
+// emit publishes an event about the given operation, which may be nil, to the display's observers.
+func (p *ProgressDisplay) emit(eventType EventType, operation *ProgressOperation, message string) {
+	if !p.events.hasSubscribers() {
+		return
+	}
+
+	event := Event{
+		Type:    eventType,
+		Time:    time.Now(),
+		Message: message,
+	}
+
+	if operation != nil {
+		p.stackMutex.RLock()
+
+		snapshot := operation.snapshot()
+		event.Operation = &snapshot
+
+		for ancestor := operation.parent; ancestor != nil; ancestor = ancestor.parent {
+			event.Ancestors = append(event.Ancestors, ancestor.snapshot())
+		}
+
+		p.stackMutex.RUnlock()
+
+		slices.Reverse(event.Ancestors)
+	}
+
+	p.events.publish(event)
+}
+
+// currentOperation returns the innermost operation, or nil if there is none.
+func (p *ProgressDisplay) currentOperation() *ProgressOperation {
+	p.stackMutex.RLock()
+	defer p.stackMutex.RUnlock()
+
+	if len(p.progressStack) == 0 {
+		return nil
+	}
+
+	return p.progressStack[len(p.progressStack)-1]
+}
+
+// snapshot copies the operation's state.
+// Note: This method assumes the caller holds a lock on the display's stackMutex.
+func (op *ProgressOperation) snapshot() OperationSnapshot {
+	return OperationSnapshot{
+		StartTime:  op.StartTime,
+		Error:      op.Error,
+		Message:    op.Message,
+		Key:        op.key,
+		Level:      op.Level,
+		Duration:   op.duration,
+		Persistent: op.Persistent,
+	}
+}
+
+// eventDispatcher delivers events to observers in order without blocking the publisher.
+// A dispatcher goroutine runs only while there are events queued.
+type eventDispatcher struct {
+	idle        *sync.Cond // signaled when the dispatcher goroutine exits
+	observers   map[int]func(Event)
+	queue       []Event
+	mutex       sync.Mutex
+	nextID      int
+	dispatching bool
+}
+
+// newEventDispatcher creates a dispatcher without observers.
+func newEventDispatcher() *eventDispatcher {
+	dispatcher := &eventDispatcher{observers: make(map[int]func(Event))}
+	dispatcher.idle = sync.NewCond(&dispatcher.mutex)
+
+	return dispatcher
+}
+
+// subscribe registers an observer and returns a function removing it.
+func (d *eventDispatcher) subscribe(observer func(Event)) func() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	id := d.nextID
+	d.nextID++
+	d.observers[id] = observer
+
+	return func() {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+
+		delete(d.observers, id)
+	}
+}
+
+// hasSubscribers reports whether anyone observes the events, so publishers can skip building them.
+func (d *eventDispatcher) hasSubscribers() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return len(d.observers) > 0
+}
+
+// publish queues an event, starting the dispatcher goroutine if it isn't running.
+func (d *eventDispatcher) publish(event Event) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.queue = append(d.queue, event)
+
+	if !d.dispatching {
+		d.dispatching = true
+
+		go d.dispatch()
+	}
+}
+
+// dispatch delivers queued events until the queue is empty.
+func (d *eventDispatcher) dispatch() {
+	for {
+		d.mutex.Lock()
+
+		if len(d.queue) == 0 {
+			d.dispatching = false
+			d.idle.Broadcast()
+			d.mutex.Unlock()
+
+			return
+		}
+
+		event := d.queue[0]
+		d.queue = d.queue[1:]
+
+		// Deliver in subscription order to keep observers' view of the display deterministic
+		ids := make([]int, 0, len(d.observers))
+		for id := range d.observers {
+			ids = append(ids, id)
+		}
+
+		slices.Sort(ids)
+
+		observers := make([]func(Event), 0, len(ids))
+		for _, id := range ids {
+			observers = append(observers, d.observers[id])
+		}
+
+		d.mutex.Unlock()
+
+		for _, observer := range observers {
+			observer(event)
+		}
+	}
+}
+
+// flush waits until all queued events were delivered.
+func (d *eventDispatcher) flush() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for d.dispatching {
+		d.idle.Wait()
+	}
+}
@@ -0,0 +1,159 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+// eventLog collects events delivered to an observer.
+type eventLog struct {
+	events []nesgress.Event
+	mutex  sync.Mutex
+}
+
+func (l *eventLog) observe(event nesgress.Event) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.events = append(l.events, event)
+}
+
+func (l *eventLog) types() []nesgress.EventType {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	types := make([]nesgress.EventType, 0, len(l.events))
+	for _, event := range l.events {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func Test_Subscribe_WithLifecycleCalls_DeliversEventsInOrder(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	var log eventLog
+	display.Subscribe(log.observe)
+
+	_ = display.Start("Parent")
+	_ = display.Start("Child")
+	_ = display.Update("Child updated")
+	_ = display.LogAccomplishment("Step")
+	_ = display.Log("Note")
+	_ = display.Pause()
+	_ = display.Resume()
+	_ = display.Fail("Child", errors.New("boom"))
+	_ = display.Finish("Parent")
+	require.NoError(t, display.Close())
+
+	require.Equal(t, []nesgress.EventType{
+		nesgress.EventStart,
+		nesgress.EventStart,
+		nesgress.EventUpdate,
+		nesgress.EventAccomplishment,
+		nesgress.EventLog,
+		nesgress.EventPause,
+		nesgress.EventResume,
+		nesgress.EventFail,
+		nesgress.EventFinish,
+	}, log.types())
+}
+
+func Test_Subscribe_WithNestedOperation_IncludesAncestrySnapshot(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	var log eventLog
+	display.Subscribe(log.observe)
+
+	_ = display.Start("Parent")
+	_ = display.Start("Child")
+	_ = display.Fail("Child", errors.New("boom"))
+	_ = display.Finish("Parent")
+	require.NoError(t, display.Close())
+
+	failed := log.events[2]
+	require.Equal(t, nesgress.EventFail, failed.Type)
+	require.Equal(t, "Child", failed.Operation.Message)
+	require.Equal(t, 1, failed.Operation.Level)
+	require.EqualError(t, failed.Operation.Error, "boom")
+	require.Len(t, failed.Ancestors, 1)
+	require.Equal(t, "Parent", failed.Ancestors[0].Message)
+}
+
+func Test_Subscribe_WithSlowObserver_DoesNotBlockDisplay(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	delivered := make(chan struct{}, 2)
+
+	display.Subscribe(func(nesgress.Event) {
+		time.Sleep(100 * time.Millisecond)
+		delivered <- struct{}{}
+	})
+
+	start := time.Now()
+
+	_ = display.Start("Quick")
+	_ = display.Finish("Quick")
+
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+
+	require.NoError(t, display.Close())
+	require.Len(t, delivered, 2)
+}
+
+func Test_Unsubscribe_AfterSubscribing_StopsDeliveringEvents(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	var log eventLog
+	unsubscribe := display.Subscribe(log.observe)
+
+	_ = display.Start("Before")
+	_ = display.Finish("Before")
+	require.NoError(t, display.Close())
+
+	unsubscribe()
+
+	_ = display.Start("After")
+	_ = display.Finish("After")
+	require.NoError(t, display.Close())
+
+	require.Len(t, log.types(), 2)
+}
+
+func Test_EventType_String_ReturnsName(t *testing.T) {
+	require.Equal(t, "accomplishment", nesgress.EventAccomplishment.String())
+	require.Equal(t, "unknown", nesgress.EventType(0).String())
+}
+
+func Test_Subscribe_WhenCleared_CancelsRunningOperationsInnermostFirst(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	var log eventLog
+	display.Subscribe(log.observe)
+
+	_ = display.Start("Parent")
+	_ = display.Start("Child")
+	require.NoError(t, display.Close())
+
+	require.Equal(t, []nesgress.EventType{
+		nesgress.EventStart,
+		nesgress.EventStart,
+		nesgress.EventCancel,
+		nesgress.EventCancel,
+	}, log.types())
+	require.Equal(t, "Child", log.events[2].Operation.Message)
+	require.Equal(t, "Parent", log.events[3].Operation.Message)
+}
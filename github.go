@@ -0,0 +1,64 @@
+package nesgress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Escaping of GitHub Actions workflow commands.
+var (
+	githubDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// GitHubDialect writes progress like PlainDialect, wrapping every top-level operation in a collapsible group
+// of the GitHub Actions log, and annotating failures as errors of the workflow run.
+func GitHubDialect() Dialect {
+	return groupDialect{
+		begin: func(title string) string {
+			return "::group::" + githubDataEscaper.Replace(title)
+		},
+		end:     "::endgroup::",
+		failure: githubErrorCommand,
+	}
+}
+
+// githubErrorCommand returns the workflow command annotating a failed operation as an error.
+func githubErrorCommand(event Event) string {
+	title := strings.Join(operationPath(event), contextSeparator)
+
+	return fmt.Sprintf(
+		"::error title=%s::%s",
+		githubPropertyEscaper.Replace(title),
+		githubDataEscaper.Replace(failureMessage(event.Operation)),
+	)
+}
+
+// failureMessage returns the error a failed operation completed with, or a generic message if it has none.
+func failureMessage(operation *OperationSnapshot) string {
+	if operation.Error == nil {
+		return operation.Message + " failed"
+	}
+
+	return operation.Error.Error()
+}
+
+// writeLines writes the given lines, skipping empty ones.
+func writeLines(w io.Writer, lines ...string) error {
+	var builder strings.Builder
+
+	for _, line := range lines {
+		if line != "" {
+			builder.WriteString(line + "\n")
+		}
+	}
+
+	if builder.Len() == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
@@ -0,0 +1,191 @@
+package nesgress
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// gitlabSectionNameInvalid matches the characters GitLab doesn't allow in section names.
+var gitlabSectionNameInvalid = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// GitLabOption configures a GitLabDialect.
+type GitLabOption func(*gitlabDialect)
+
+// gitlabDialect writes progress as plain text in collapsible sections of the GitLab CI job log.
+type gitlabDialect struct {
+	open     []gitlabSection // sections opened and not yet ended, outermost first
+	sections int             // number of sections opened so far, keeping their names unique
+	nested   bool            // whether nested operations open sections as well
+	expanded bool            // whether sections start expanded
+}
+
+// gitlabSection is a section of the GitLab CI job log.
+type gitlabSection struct {
+	name   string
+	lines  []string // plain text written in the section, replayed in an expanded section if anything in it fails
+	start  int64    // Unix timestamp of the section's start, from which GitLab computes its duration
+	level  int      // nesting level of the operation the section wraps
+	failed bool     // whether the operation the section wraps, or any operation nested in it, failed
+}
+
+// GitLabDialect writes progress like PlainDialect, wrapping every top-level operation in a collapsed section
+// of the GitLab CI job log, headed by the operation's message:
+//
+//	section_start:1735830245:installing_packages_1[collapsed=true]
+//	Installing packages
+//	  Downloading zlib...
+//	  [ok] Downloading zlib (took 1.2s)
+//	section_end:1735830248:installing_packages_1
+//	[ok] Installing packages (took 3s)
+//
+// Outcomes follow their section, so they show while it's collapsed. GitLab settles whether a section
+// is collapsed when it starts, so the lines of a collapsed section in which any operation failed are repeated
+// in an expanded section right after it, followed by the outcome.
+//
+// GitLab shows the duration of each section, computed from its timestamps. Those are whole seconds,
+// so outcomes carry the duration of their operation instead, which agrees with other dialects.
+func GitLabDialect(opts ...GitLabOption) Dialect {
+	dialect := &gitlabDialect{}
+	for _, opt := range opts {
+		opt(dialect)
+	}
+
+	return dialect
+}
+
+// WithNestedSections wraps nested operations in sections of their own too, nested in their parent's section.
+func WithNestedSections() GitLabOption {
+	return func(g *gitlabDialect) {
+		g.nested = true
+	}
+}
+
+// WithExpandedSections starts sections expanded instead of collapsed.
+func WithExpandedSections() GitLabOption {
+	return func(g *gitlabDialect) {
+		g.expanded = true
+	}
+}
+
+// WriteEvent writes the lines of an event, along with the section markers it calls for.
+func (g *gitlabDialect) WriteEvent(w io.Writer, event Event) error {
+	ends := event.Type == EventFinish || event.Type == EventFail || event.Type == EventCancel
+
+	if event.Type == EventFail {
+		g.markFailure(event.Operation.Level)
+	}
+
+	switch {
+	case event.Type == EventStart && g.wraps(event.Operation):
+		g.record(plainText(event))
+
+		return writeLines(w, g.startSection(event))
+	case ends && g.wraps(event.Operation):
+		return writeLines(w, g.endSections(event)...)
+	default:
+		text := plainText(event)
+		g.record(text)
+
+		return writeLines(w, text)
+	}
+}
+
+// record keeps a line of plain text in the open sections, should their operation fail.
+// Expanded sections show their lines already, so nothing is kept for them.
+func (g *gitlabDialect) record(text string) {
+	if g.expanded || text == "" {
+		return
+	}
+
+	for i := range g.open {
+		g.open[i].lines = append(g.open[i].lines, text)
+	}
+}
+
+// markFailure marks the open sections enclosing a failed operation at the given nesting level,
+// along with its own section, so they're expanded once they end.
+func (g *gitlabDialect) markFailure(level int) {
+	for i := range g.open {
+		if g.open[i].level <= level {
+			g.open[i].failed = true
+		}
+	}
+}
+
+// wraps reports whether the given operation has a section of its own.
+func (g *gitlabDialect) wraps(operation *OperationSnapshot) bool {
+	return operation != nil && (operation.Level == 0 || g.nested)
+}
+
+// startSection opens a section for the operation of an event and returns its header.
+// The header stands in for the start line.
+func (g *gitlabDialect) startSection(event Event) string {
+	g.sections++
+
+	section := gitlabSection{
+		name:  gitlabSectionName(event.Operation.Message, g.sections),
+		start: event.Time.Unix(),
+		level: event.Operation.Level,
+	}
+	g.open = append(g.open, section)
+
+	options := "[collapsed=true]"
+	if g.expanded {
+		options = ""
+	}
+
+	return fmt.Sprintf(
+		"\033[0Ksection_start:%d:%s%s\r\033[0K%s%s",
+		section.start, section.name, options,
+		strings.Repeat(plainIndentUnit, section.level), event.Operation.Message,
+	)
+}
+
+// endSections ends the section of the operation of an event, along with any sections nested in it
+// that were left open, e.g. by timed out operations, and returns their end markers followed by the outcome.
+// The lines of a section in which any operation failed are repeated in an expanded section before the outcome.
+func (g *gitlabDialect) endSections(event Event) []string {
+	var (
+		lines   []string
+		section gitlabSection
+	)
+
+	end := event.Time.Unix()
+
+	for len(g.open) > 0 && g.open[len(g.open)-1].level >= event.Operation.Level {
+		section = g.open[len(g.open)-1]
+		g.open = g.open[:len(g.open)-1]
+
+		lines = append(lines, fmt.Sprintf("\033[0Ksection_end:%d:%s\r\033[0K", end, section.name))
+	}
+
+	if section.failed && len(section.lines) > 0 {
+		name := section.name + "_failed"
+
+		header := event.Operation.Message + " had failures"
+		if event.Type == EventFail {
+			header = event.Operation.Message + " failed"
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"\033[0Ksection_start:%d:%s\r\033[0K%s%s",
+			end, name, strings.Repeat(plainIndentUnit, section.level), header,
+		))
+		lines = append(lines, section.lines...)
+		lines = append(lines, fmt.Sprintf("\033[0Ksection_end:%d:%s\r\033[0K", end, name))
+	}
+
+	outcome := plainText(event)
+	g.record(outcome)
+
+	return append(lines, outcome)
+}
+
+// gitlabSectionName derives a unique section name from the message of an operation.
+func gitlabSectionName(message string, number int) string {
+	slug := strings.Trim(gitlabSectionNameInvalid.ReplaceAllString(strings.ToLower(message), "_"), "_")
+
+	return fmt.Sprintf("%s_%d", slug, number)
+}
@@ -0,0 +1,79 @@
+package nesgress
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// glyphSet holds the symbols the display draws with.
+type glyphSet struct {
+	success    string
+	failure    string
+	cause      string   // introduces the cause of a wrapped error
+	branch     string   // connects a tree node followed by siblings
+	lastBranch string   // connects the last node of a tree level
+	trunk      string   // continues a tree level past the lines nested in a node
+	ellipsis   string   // stands in for text left out of the live line
+	spinner    []string // frames drawn one after the other in front of the active operation
+}
+
+// unicodeGlyphs are drawn by default.
+var unicodeGlyphs = glyphSet{
+	spinner:    []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"},
+	success:    "✓",
+	failure:    "✗",
+	cause:      "↳ ",
+	branch:     "├─ ",
+	lastBranch: "└─ ",
+	trunk:      "│  ",
+	ellipsis:   "…",
+}
+
+// asciiGlyphs are drawn on terminals that can't render the Unicode ones.
+var asciiGlyphs = glyphSet{
+	spinner:    []string{"|", "/", "-", "\\"},
+	success:    "[ok]",
+	failure:    "[FAIL]",
+	cause:      "-> ",
+	branch:     "|- ",
+	lastBranch: "`- ",
+	trunk:      "|  ",
+	ellipsis:   "...",
+}
+
+// asciiTerminals are the values of TERM naming terminals known to lack the Unicode glyphs,
+// such as the Linux console and serial terminals.
+var asciiTerminals = []string{"linux", "dumb", "vt100", "vt102", "vt220"}
+
+// detectGlyphs picks the glyphs a terminal can render, judging by its TERM and the character encoding of the locale.
+// Output that isn't a terminal gets the Unicode glyphs, as it's read elsewhere.
+func detectGlyphs(output io.Writer) *glyphSet {
+	if !isTerminal(output) {
+		return &unicodeGlyphs
+	}
+
+	for _, terminal := range asciiTerminals {
+		if os.Getenv("TERM") == terminal {
+			return &asciiGlyphs
+		}
+	}
+
+	// The first locale variable that's set determines the encoding, no locale at all is common in containers
+	for _, variable := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := strings.ToLower(os.Getenv(variable)); locale != "" {
+			if !strings.Contains(locale, "utf-8") && !strings.Contains(locale, "utf8") {
+				return &asciiGlyphs
+			}
+
+			break
+		}
+	}
+
+	return &unicodeGlyphs
+}
+
+// spinnerFrame returns the spinner glyph of the given frame.
+func (g *glyphSet) spinnerFrame(frame int) string {
+	return g.spinner[frame%len(g.spinner)]
+}
@@ -0,0 +1,93 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+// requireASCII fails the test if the output contains anything but ASCII.
+func requireASCII(t *testing.T, output string) {
+	t.Helper()
+
+	for _, r := range output {
+		require.LessOrEqual(t, r, rune(unicode.MaxASCII), "non-ASCII %q in output %q", r, output)
+	}
+}
+
+func Test_ASCII_WithCompletions_DrawsASCIIGlyphs(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithASCII(true))
+
+	_ = display.StartPersistent("Deploying")
+	_ = display.LogAccomplishment("Built container")
+	_ = display.FinishPersistent("Deployed")
+	_ = display.Start("Uploading")
+	_ = display.Fail("Upload failed", fmt.Errorf("upload: %w", errors.New("connection refused")))
+
+	require.NoError(t, display.Close())
+
+	output := display.GetOutputSafely()
+	require.Contains(t, output, "[ok] Built container")
+	require.Contains(t, output, "[ok] Deploying")
+	require.Contains(t, output, "[FAIL] Uploading")
+	require.Contains(t, output, "-> connection refused")
+	requireASCII(t, output)
+}
+
+func Test_ASCII_WithTreeOutput_DrawsASCIIConnectors(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithASCII(true), nesgress.WithTreeOutput())
+
+	_ = display.Start("Setting up")
+	_ = display.Start("Downloading")
+	_ = display.Finish("Downloaded")
+	_ = display.Start("Compiling")
+	_ = display.Finish("Compiled")
+	_ = display.Finish("Set up")
+
+	require.NoError(t, display.Close())
+
+	output := display.GetOutputSafely()
+	require.Contains(t, output, "|- [ok] Downloading")
+	require.Contains(t, output, "`- [ok] Compiling")
+	requireASCII(t, output)
+}
+
+func Test_ASCII_WithRunningOperation_DrawsASCIISpinner(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithASCII(true))
+
+	_ = display.Start("Installing")
+
+	frame := eventuallyDrawn(t, display, "Installing")
+
+	require.True(t, strings.ContainsAny(frame[:1], `|/-\`), "frame %q", frame)
+
+	_ = display.Finish("Installed")
+
+	require.NoError(t, display.Close())
+	requireASCII(t, display.GetOutputSafely())
+}
+
+func Test_ASCII_WhenDisabled_DrawsUnicodeGlyphs(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithASCII(false))
+
+	_ = display.Start("Installing")
+	_ = display.Finish("Installed")
+
+	require.NoError(t, display.Close())
+	require.Contains(t, display.GetOutputSafely(), "✓ Installing")
+}
@@ -0,0 +1,36 @@
+package nesgress
+
+import (
+	"io"
+)
+
+// groupDialect writes progress as plain text with the log commands of a CI system,
+// wrapping every top-level operation in a collapsible group and reporting failures as errors of the run.
+type groupDialect struct {
+	begin   func(title string) string // returns the command beginning a group with the given title
+	failure func(event Event) string  // returns the command reporting the failure of an event's operation
+	end     string                    // command ending the current group
+}
+
+// WriteEvent writes the lines of an event, along with the log commands it calls for.
+func (g groupDialect) WriteEvent(w io.Writer, event Event) error {
+	topLevel := event.Operation != nil && event.Operation.Level == 0
+
+	// The group's title stands in for the start line, groups can't be nested
+	if event.Type == EventStart && topLevel {
+		return writeLines(w, g.begin(event.Operation.Message))
+	}
+
+	var lines []string
+
+	if topLevel && (event.Type == EventFinish || event.Type == EventFail || event.Type == EventCancel) {
+		// Outcomes are written after the group, so they stay visible while it's collapsed
+		lines = append(lines, g.end)
+	}
+
+	if event.Type == EventFail {
+		lines = append(lines, g.failure(event))
+	}
+
+	return writeLines(w, append(lines, plainText(event))...)
+}
@@ -0,0 +1,210 @@
+package nesgress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// historyWindow is the number of most recent durations kept per operation.
+const historyWindow = 10
+
+// historyVersion is the version of the history file format.
+const historyVersion = 1
+
+// historyKeySeparator joins the keys of an operation and its ancestors into its history key.
+const historyKeySeparator = "/"
+
+// History records how long operations took on previous runs, so indeterminate operations
+// can show an estimate of their remaining time.
+//
+// Operations are keyed by their path of operation keys, which default to the message they were started with.
+// Use SetKey to give operations with varying messages a stable key.
+type History struct {
+	operations map[string][]time.Duration
+	path       string
+	mutex      sync.RWMutex
+}
+
+// historyFile is the on-disk format of a History.
+type historyFile struct {
+	Operations map[string][]time.Duration `json:"operations"`
+	Version    int                        `json:"version"`
+}
+
+// OpenHistory loads the history with the given name from the user cache directory,
+// e.g. ~/.cache/nesgress/<name>.json on Linux. A missing file yields an empty history.
+func OpenHistory(name string) (*History, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("locate cache directory: %w", err)
+	}
+
+	return LoadHistory(filepath.Join(cacheDir, "nesgress", name+".json"))
+}
+
+// LoadHistory loads the history stored at the given path. A missing file yields an empty history.
+func LoadHistory(path string) (*History, error) {
+	history := &History{
+		operations: make(map[string][]time.Duration),
+		path:       path,
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return history, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	var file historyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse history %s: %w", path, err)
+	}
+
+	if file.Version == historyVersion && file.Operations != nil {
+		history.operations = file.Operations
+	}
+
+	return history, nil
+}
+
+// Record adds a duration of the operation with the given key, keeping only the most recent ones.
+func (h *History) Record(key string, duration time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	durations := append(h.operations[key], duration)
+	if len(durations) > historyWindow {
+		durations = durations[len(durations)-historyWindow:]
+	}
+
+	h.operations[key] = durations
+}
+
+// Estimate returns the median of the recorded durations of the operation with the given key.
+func (h *History) Estimate(key string) (time.Duration, bool) {
+	h.mutex.RLock()
+	durations := slices.Clone(h.operations[key])
+	h.mutex.RUnlock()
+
+	if len(durations) == 0 {
+		return 0, false
+	}
+
+	slices.Sort(durations)
+
+	middle := len(durations) / 2
+	if len(durations)%2 == 0 {
+		return (durations[middle-1] + durations[middle]) / 2, true
+	}
+
+	return durations[middle], true
+}
+
+// Save writes the history back to the file it was loaded from.
+// The file is replaced atomically, so concurrent runs never see a partial file.
+func (h *History) Save() error {
+	h.mutex.RLock()
+	data, err := json.MarshalIndent(historyFile{Version: historyVersion, Operations: h.operations}, "", "  ")
+	h.mutex.RUnlock()
+
+	if err != nil {
+		return fmt.Errorf("encode history: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o750); err != nil {
+		return fmt.Errorf("create history directory: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".*")
+	if err != nil {
+		return fmt.Errorf("create history file: %w", err)
+	}
+
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		_ = temp.Close()
+		return fmt.Errorf("write history: %w", err)
+	}
+
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+
+	if err := os.Rename(temp.Name(), h.path); err != nil {
+		return fmt.Errorf("replace history: %w", err)
+	}
+
+	return nil
+}
+
+// SetKey sets the key the current operation is recorded under in the display's history.
+// Operations keep the message they were started with as their key unless given another one.
+func (p *ProgressDisplay) SetKey(key string) error {
+	p.stackMutex.Lock()
+	defer p.stackMutex.Unlock()
+
+	if len(p.progressStack) == 0 {
+		return nil
+	}
+
+	p.progressStack[len(p.progressStack)-1].key = key
+
+	return nil
+}
+
+// historyKey returns the key of an operation's path, made of its own key and those of its ancestors.
+func (p *ProgressDisplay) historyKey(operation *ProgressOperation) string {
+	p.stackMutex.RLock()
+	defer p.stackMutex.RUnlock()
+
+	var keys []string
+
+	for current := operation; current != nil; current = current.parent {
+		keys = append(keys, current.key)
+	}
+
+	slices.Reverse(keys)
+
+	return strings.Join(keys, historyKeySeparator)
+}
+
+// recordDuration adds the duration of a successful operation to the display's history.
+func (p *ProgressDisplay) recordDuration(operation *ProgressOperation) {
+	if p.history == nil || !operation.Success {
+		return
+	}
+
+	p.history.Record(p.historyKey(operation), operation.duration)
+}
+
+// estimateLabel returns the estimated remaining time of an operation based on its history,
+// or an empty string when there is no history for it.
+func (p *ProgressDisplay) estimateLabel(operation *ProgressOperation) string {
+	if p.history == nil {
+		return ""
+	}
+
+	usual, ok := p.history.Estimate(p.historyKey(operation))
+	if !ok {
+		return ""
+	}
+
+	remaining := usual - time.Since(operation.StartTime)
+	if remaining <= 0 {
+		return fmt.Sprintf("(usually %v)", roundLiveDuration(usual))
+	}
+
+	return fmt.Sprintf("(~%v remaining, usually %v)", roundLiveDuration(remaining), roundLiveDuration(usual))
+}
@@ -0,0 +1,114 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_LoadHistory_WithMissingFile_ReturnsEmptyHistory(t *testing.T) {
+	history, err := nesgress.LoadHistory(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+
+	_, ok := history.Estimate("anything")
+	require.False(t, ok)
+}
+
+func Test_LoadHistory_WithCorruptFile_ReturnsError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+
+	_, err := nesgress.LoadHistory(path)
+	require.Error(t, err)
+}
+
+func Test_HistoryEstimate_WithRecordedDurations_ReturnsMedian(t *testing.T) {
+	history, _ := nesgress.LoadHistory(filepath.Join(t.TempDir(), "history.json"))
+
+	history.Record("build", 10*time.Second)
+	history.Record("build", 90*time.Second)
+	history.Record("build", 20*time.Second)
+
+	estimate, ok := history.Estimate("build")
+	require.True(t, ok)
+	require.Equal(t, 20*time.Second, estimate)
+}
+
+func Test_HistoryEstimate_WithManyRecordedDurations_UsesOnlyRecentOnes(t *testing.T) {
+	history, _ := nesgress.LoadHistory(filepath.Join(t.TempDir(), "history.json"))
+
+	for range 10 {
+		history.Record("build", time.Minute)
+	}
+
+	for range 10 {
+		history.Record("build", time.Second)
+	}
+
+	estimate, _ := history.Estimate("build")
+	require.Equal(t, time.Second, estimate)
+}
+
+func Test_HistorySave_ThenLoad_KeepsRecordedDurations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.json")
+	history, _ := nesgress.LoadHistory(path)
+	history.Record("build", 3*time.Second)
+
+	require.NoError(t, history.Save())
+
+	reloaded, err := nesgress.LoadHistory(path)
+	require.NoError(t, err)
+
+	estimate, ok := reloaded.Estimate("build")
+	require.True(t, ok)
+	require.Equal(t, 3*time.Second, estimate)
+}
+
+func Test_ProgressDisplayWithHistory_OnClose_RecordsSuccessfulOperationPaths(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	history, _ := nesgress.LoadHistory(path)
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithHistory(history))
+
+	_ = display.Start("Deploying")
+	_ = display.Start("Downloading 3/10")
+	_ = display.SetKey("download")
+	_ = display.Finish("Downloaded")
+	_ = display.Finish("Deployed")
+	require.NoError(t, display.Close())
+
+	reloaded, err := nesgress.LoadHistory(path)
+	require.NoError(t, err)
+
+	_, ok := reloaded.Estimate("Deploying/download")
+	require.True(t, ok)
+
+	_, ok = reloaded.Estimate("Deploying")
+	require.True(t, ok)
+}
+
+func Test_ProgressDisplayWithHistory_WithPreviousRuns_ShowsRemainingTime(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	history, _ := nesgress.LoadHistory(filepath.Join(t.TempDir(), "history.json"))
+	history.Record("Deploying", 70*time.Second)
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithHistory(history))
+
+	_ = display.Start("Deploying")
+	time.Sleep(100 * time.Millisecond)
+
+	require.Contains(t, display.GetOutputSafely(), "Deploying (~1m9s remaining, usually 1m10s)")
+
+	_ = display.Finish("Deployed")
+}
@@ -0,0 +1,321 @@
+package nesgress
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSkipped marks operations failed with it, or an error wrapping it, as skipped in JUnit reports.
+var ErrSkipped = errors.New("operation skipped")
+
+// Errors of operations that didn't complete, recorded as their failure.
+var (
+	errInterrupted = errors.New("interrupted by the completion of an enclosing operation")
+	errCanceled    = errors.New("canceled before completing")
+)
+
+// junitTimestampLayout is the layout of JUnit timestamps, which have no time zone.
+const junitTimestampLayout = "2006-01-02T15:04:05"
+
+// JUnitReport collects the tree of finished operations from their events, and writes it as a JUnit XML report
+// that CI systems render as a dashboard of passed and failed steps.
+//
+// Top-level operations become test suites, and the operations nested in them become test cases,
+// named after the path of their parents within the suite. Failures become failures of their test case,
+// unless their error wraps ErrSkipped, which marks the test case as skipped instead.
+// Operations canceled by Clear or Close, or still running when an enclosing operation completes, fail as well.
+// Accomplishments and log lines are recorded as the output of their test case.
+type JUnitReport struct {
+	name  string
+	roots []*junitNode // top-level operations, in the order they started
+	open  []*junitNode // operations started and not yet completed, outermost first
+	mutex sync.Mutex
+}
+
+// junitNode is an operation collected by a JUnitReport.
+type junitNode struct {
+	start    time.Time
+	err      error
+	name     string
+	output   strings.Builder
+	children []*junitNode
+	duration time.Duration
+	finished bool
+}
+
+// NewJUnitReport creates an empty report with the given name, e.g. the name of the program.
+func NewJUnitReport(name string) *JUnitReport {
+	return &JUnitReport{name: name}
+}
+
+// Observe records an event in the report. It suits ProgressDisplay.Subscribe:
+//
+//	report := nesgress.NewJUnitReport("provision")
+//	unsubscribe := display.Subscribe(report.Observe)
+func (r *JUnitReport) Observe(event Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if event.Operation == nil {
+		return
+	}
+
+	level := event.Operation.Level
+
+	switch event.Type {
+	case EventStart:
+		node := &junitNode{name: event.Operation.Message, start: event.Operation.StartTime}
+
+		// Operations cleared without completing, or outlasting their parent, leave the stack deeper than the new one
+		r.open = r.open[:min(level, len(r.open))]
+
+		if len(r.open) == 0 {
+			r.roots = append(r.roots, node)
+		} else {
+			parent := r.open[len(r.open)-1]
+			parent.children = append(parent.children, node)
+		}
+
+		r.open = append(r.open, node)
+	case EventUpdate:
+		if node := r.nodeAt(level); node != nil {
+			node.name = event.Operation.Message
+		}
+	case EventFinish, EventFail:
+		if node := r.nodeAt(level); node != nil {
+			err := event.Operation.Error
+			if event.Type == EventFail && err == nil {
+				err = errors.New(failureMessage(event.Operation))
+			}
+
+			node.complete(event.Operation.Duration, err)
+
+			// Operations still running within it, e.g. when it timed out, keep their place in the stack,
+			// so they're recorded with their own outcome if they complete after all
+			for _, nested := range r.open[level+1:] {
+				if !nested.finished {
+					nested.complete(event.Time.Sub(nested.start), errInterrupted)
+				}
+			}
+		}
+	case EventCancel:
+		if node := r.nodeAt(level); node != nil {
+			node.complete(event.Operation.Duration, errCanceled)
+		}
+	case EventAccomplishment, EventLog:
+		if node := r.nodeAt(level); node != nil {
+			node.output.WriteString(event.Message + "\n")
+		}
+	default:
+		// Pauses don't show in the report
+	}
+}
+
+// complete records the outcome of an operation.
+func (n *junitNode) complete(duration time.Duration, err error) {
+	n.finished = true
+	n.duration = duration
+	n.err = err
+}
+
+// nodeAt returns the open operation at the given nesting level, or nil if there is none.
+// Note: This method assumes the caller holds the mutex.
+func (r *JUnitReport) nodeAt(level int) *junitNode {
+	if level >= len(r.open) {
+		return nil
+	}
+
+	return r.open[level]
+}
+
+// Dialect returns a dialect recording events in the report before writing them in the given dialect,
+// so line reporters can fill a report as well:
+//
+//	reporter := nesgress.NewLineReporter(os.Stdout, report.Dialect(nesgress.PlainDialect()))
+func (r *JUnitReport) Dialect(dialect Dialect) Dialect {
+	return junitDialect{report: r, dialect: dialect}
+}
+
+// junitDialect records events in a report before writing them in another dialect.
+type junitDialect struct {
+	report  *JUnitReport
+	dialect Dialect
+}
+
+// WriteEvent records the event in the report and writes it in the wrapped dialect.
+func (j junitDialect) WriteEvent(w io.Writer, event Event) error {
+	j.report.Observe(event)
+
+	return j.dialect.WriteEvent(w, event)
+}
+
+// WriteTo writes the report of the operations that finished so far as JUnit XML.
+func (r *JUnitReport) WriteTo(w io.Writer) (int64, error) {
+	r.mutex.Lock()
+	document := r.document()
+	r.mutex.Unlock()
+
+	encoded, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("encode JUnit report: %w", err)
+	}
+
+	written, err := io.WriteString(w, xml.Header+string(encoded)+"\n")
+
+	return int64(written), err
+}
+
+// Save writes the report to the file at the given path, replacing it if it exists.
+func (r *JUnitReport) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create JUnit report: %w", err)
+	}
+
+	if _, err := r.WriteTo(file); err != nil {
+		return errors.Join(err, file.Close())
+	}
+
+	return file.Close()
+}
+
+// XML documents of JUnit reports.
+type (
+	junitTestSuites struct {
+		XMLName  xml.Name         `xml:"testsuites"`
+		Name     string           `xml:"name,attr,omitempty"`
+		Time     string           `xml:"time,attr"`
+		Suites   []junitTestSuite `xml:"testsuite"`
+		Tests    int              `xml:"tests,attr"`
+		Failures int              `xml:"failures,attr"`
+		Skipped  int              `xml:"skipped,attr"`
+	}
+
+	junitTestSuite struct {
+		Name      string          `xml:"name,attr"`
+		Timestamp string          `xml:"timestamp,attr"`
+		Time      string          `xml:"time,attr"`
+		Cases     []junitTestCase `xml:"testcase"`
+		Tests     int             `xml:"tests,attr"`
+		Failures  int             `xml:"failures,attr"`
+		Skipped   int             `xml:"skipped,attr"`
+	}
+
+	junitTestCase struct {
+		Failure   *junitResult `xml:"failure,omitempty"`
+		Skipped   *junitResult `xml:"skipped,omitempty"`
+		Name      string       `xml:"name,attr"`
+		Classname string       `xml:"classname,attr"`
+		Time      string       `xml:"time,attr"`
+		SystemOut string       `xml:"system-out,omitempty"` //nolint:tagliatelle // Named by the JUnit format
+	}
+
+	junitResult struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// document builds the XML document of the operations that finished so far.
+// Note: This method assumes the caller holds the mutex.
+func (r *JUnitReport) document() junitTestSuites {
+	document := junitTestSuites{Name: r.name}
+
+	var total time.Duration
+
+	for _, root := range r.roots {
+		if !root.finished {
+			continue
+		}
+
+		suite := junitTestSuite{
+			Name:      root.name,
+			Timestamp: root.start.Format(junitTimestampLayout),
+			Time:      junitSeconds(root.duration),
+		}
+
+		if len(root.children) == 0 {
+			suite.add(root, root.name)
+		} else {
+			suite.addTree(root, root.name)
+		}
+
+		document.Suites = append(document.Suites, suite)
+		document.Tests += suite.Tests
+		document.Failures += suite.Failures
+		document.Skipped += suite.Skipped
+		total += root.duration
+	}
+
+	document.Time = junitSeconds(total)
+
+	return document
+}
+
+// addTree adds the finished operations nested in the given one as test cases of the suite, leaves first.
+// An operation with nested operations becomes a test case of its own only if it failed while none of them did,
+// so its failure isn't lost.
+func (s *junitTestSuite) addTree(node *junitNode, classname string) bool {
+	failedWithin := false
+
+	for _, child := range node.children {
+		if !child.finished {
+			continue
+		}
+
+		if len(child.children) == 0 {
+			s.add(child, classname)
+			failedWithin = failedWithin || child.failed()
+		} else {
+			failedWithin = s.addTree(child, classname+contextSeparator+child.name) || failedWithin
+		}
+	}
+
+	if node.err != nil && !failedWithin {
+		s.add(node, classname)
+		failedWithin = node.failed()
+	}
+
+	return failedWithin
+}
+
+// failed reports whether the operation failed, rather than succeeded or was skipped.
+func (n *junitNode) failed() bool {
+	return n.err != nil && !errors.Is(n.err, ErrSkipped)
+}
+
+// add adds an operation as a test case of the suite.
+func (s *junitTestSuite) add(node *junitNode, classname string) {
+	testCase := junitTestCase{
+		Name:      node.name,
+		Classname: classname,
+		Time:      junitSeconds(node.duration),
+		SystemOut: node.output.String(),
+	}
+
+	switch {
+	case errors.Is(node.err, ErrSkipped):
+		testCase.Skipped = &junitResult{Message: node.err.Error()}
+		s.Skipped++
+	case node.err != nil:
+		testCase.Failure = &junitResult{
+			Message: node.err.Error(),
+			Text:    renderError(node.err, "", asciiGlyphs.cause, true),
+		}
+		s.Failures++
+	}
+
+	s.Cases = append(s.Cases, testCase)
+	s.Tests++
+}
+
+// junitSeconds formats a duration as the seconds of JUnit times.
+func junitSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
@@ -0,0 +1,237 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+// junitTestCase is the part of a JUnit test case the tests check.
+type junitTestCase struct {
+	Failure *struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	} `xml:"failure"`
+	Skipped *struct {
+		Message string `xml:"message,attr"`
+	} `xml:"skipped"`
+	Name      string `xml:"name,attr"`
+	Classname string `xml:"classname,attr"`
+	SystemOut string `xml:"system-out"`
+}
+
+// junitTestSuites is the part of a JUnit report the tests check.
+type junitTestSuites struct {
+	Suites []struct {
+		Name     string          `xml:"name,attr"`
+		Cases    []junitTestCase `xml:"testcase"`
+		Tests    int             `xml:"tests,attr"`
+		Failures int             `xml:"failures,attr"`
+		Skipped  int             `xml:"skipped,attr"`
+	} `xml:"testsuite"`
+	Tests    int `xml:"tests,attr"`
+	Failures int `xml:"failures,attr"`
+	Skipped  int `xml:"skipped,attr"`
+}
+
+// decodeJUnit writes the report and decodes it.
+func decodeJUnit(t *testing.T, report *nesgress.JUnitReport) junitTestSuites {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	_, err := report.WriteTo(&buf)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte(xml.Header)))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+
+	return suites
+}
+
+func Test_JUnitReport_WithNestedOperations_MapsLeavesToTestCases(t *testing.T) {
+	report := nesgress.NewJUnitReport("provision")
+	reporter := nesgress.NewLineReporter(io.Discard, report.Dialect(nesgress.PlainDialect()))
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading")
+	_ = reporter.Start("zlib")
+	_ = reporter.Finish("Downloaded zlib")
+	_ = reporter.Start("openssl")
+	_ = reporter.Fail("Download failed", fmt.Errorf("fetch: %w", errors.New("404 Not Found")))
+	_ = reporter.Finish("Downloaded")
+	_ = reporter.Start("Configuring")
+	_ = reporter.LogAccomplishment("Wrote config")
+	_ = reporter.Finish("Configured")
+	_ = reporter.Finish("Installed")
+
+	suites := decodeJUnit(t, report)
+
+	require.Len(t, suites.Suites, 1)
+	require.Equal(t, 3, suites.Tests)
+	require.Equal(t, 1, suites.Failures)
+
+	suite := suites.Suites[0]
+	require.Equal(t, "Installing", suite.Name)
+	require.Equal(t, 3, suite.Tests)
+	require.Equal(t, 1, suite.Failures)
+
+	require.Len(t, suite.Cases, 3)
+	require.Equal(t, "zlib", suite.Cases[0].Name)
+	require.Equal(t, "Installing: Downloading", suite.Cases[0].Classname)
+	require.Nil(t, suite.Cases[0].Failure)
+
+	require.Equal(t, "openssl", suite.Cases[1].Name)
+	require.NotNil(t, suite.Cases[1].Failure)
+	require.Equal(t, "fetch: 404 Not Found", suite.Cases[1].Failure.Message)
+	require.Contains(t, suite.Cases[1].Failure.Text, "404 Not Found")
+
+	require.Equal(t, "Configuring", suite.Cases[2].Name)
+	require.Equal(t, "Installing", suite.Cases[2].Classname)
+	require.Equal(t, "Wrote config\n", suite.Cases[2].SystemOut)
+}
+
+func Test_JUnitReport_WithSkippedOperation_MarksTestCaseSkipped(t *testing.T) {
+	report := nesgress.NewJUnitReport("provision")
+	reporter := nesgress.NewLineReporter(io.Discard, report.Dialect(nesgress.PlainDialect()))
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Installing drivers")
+	_ = reporter.Fail("Skipped drivers", fmt.Errorf("no GPU: %w", nesgress.ErrSkipped))
+	_ = reporter.Finish("Installed")
+
+	suites := decodeJUnit(t, report)
+
+	require.Equal(t, 1, suites.Skipped)
+	require.Zero(t, suites.Failures)
+	require.NotNil(t, suites.Suites[0].Cases[0].Skipped)
+	require.Equal(t, "no GPU: operation skipped", suites.Suites[0].Cases[0].Skipped.Message)
+}
+
+func Test_JUnitReport_WithParentFailingAlone_KeepsItsFailure(t *testing.T) {
+	report := nesgress.NewJUnitReport("provision")
+	reporter := nesgress.NewLineReporter(io.Discard, report.Dialect(nesgress.PlainDialect()))
+
+	_ = reporter.Start("Deploying")
+	_ = reporter.Start("Uploading")
+	_ = reporter.Finish("Uploaded")
+	_ = reporter.Fail("Deploy failed", errors.New("health check failed"))
+	_ = reporter.Start("Cleaning up")
+	_ = reporter.Finish("Cleaned up")
+
+	suites := decodeJUnit(t, report)
+
+	require.Len(t, suites.Suites, 2)
+	require.Len(t, suites.Suites[0].Cases, 2)
+	require.Equal(t, "Deploying", suites.Suites[0].Cases[1].Name)
+	require.Equal(t, "health check failed", suites.Suites[0].Cases[1].Failure.Message)
+
+	// Top-level operations without nested ones are test cases of their own suite
+	require.Equal(t, "Cleaning up", suites.Suites[1].Cases[0].Name)
+}
+
+func Test_JUnitReport_WithParentFailingWhileChildRuns_FailsChildAsInterrupted(t *testing.T) {
+	report := nesgress.NewJUnitReport("provision")
+	start := time.Now()
+	parent := nesgress.OperationSnapshot{StartTime: start, Message: "Deploying"}
+	child := nesgress.OperationSnapshot{StartTime: start, Message: "Uploading", Level: 1}
+
+	report.Observe(nesgress.Event{Type: nesgress.EventStart, Time: start, Operation: &parent})
+	report.Observe(nesgress.Event{Type: nesgress.EventStart, Time: start, Operation: &child})
+
+	failed := parent
+	failed.Error = errors.New("deploy timed out")
+	report.Observe(nesgress.Event{Type: nesgress.EventFail, Time: start.Add(time.Second), Operation: &failed})
+
+	suites := decodeJUnit(t, report)
+
+	require.Len(t, suites.Suites, 1)
+	require.Len(t, suites.Suites[0].Cases, 1)
+	require.Equal(t, "Uploading", suites.Suites[0].Cases[0].Name)
+	require.NotNil(t, suites.Suites[0].Cases[0].Failure)
+	require.Contains(t, suites.Suites[0].Cases[0].Failure.Message, "interrupted")
+}
+
+func Test_JUnitReport_WithChildCompletingAfterParent_RecordsItsOwnOutcome(t *testing.T) {
+	report := nesgress.NewJUnitReport("provision")
+	start := time.Now()
+	parent := nesgress.OperationSnapshot{StartTime: start, Message: "Deploying"}
+	child := nesgress.OperationSnapshot{StartTime: start, Message: "Uploading", Level: 1}
+
+	report.Observe(nesgress.Event{Type: nesgress.EventStart, Time: start, Operation: &parent})
+	report.Observe(nesgress.Event{Type: nesgress.EventStart, Time: start, Operation: &child})
+
+	failed := parent
+	failed.Error = errors.New("deploy timed out")
+	report.Observe(nesgress.Event{Type: nesgress.EventFail, Time: start.Add(time.Second), Operation: &failed})
+	report.Observe(nesgress.Event{Type: nesgress.EventFinish, Time: start.Add(2 * time.Second), Operation: &child})
+
+	suites := decodeJUnit(t, report)
+
+	require.Len(t, suites.Suites[0].Cases, 2)
+	require.Equal(t, "Uploading", suites.Suites[0].Cases[0].Name)
+	require.Nil(t, suites.Suites[0].Cases[0].Failure)
+	require.Equal(t, "Deploying", suites.Suites[0].Cases[1].Name)
+	require.Equal(t, "deploy timed out", suites.Suites[0].Cases[1].Failure.Message)
+}
+
+func Test_JUnitReport_WhenCleared_FailsRunningOperationsAsCanceled(t *testing.T) {
+	report := nesgress.NewJUnitReport("provision")
+	reporter := nesgress.NewLineReporter(io.Discard, report.Dialect(nesgress.PlainDialect()))
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading")
+	require.NoError(t, reporter.Close())
+
+	suites := decodeJUnit(t, report)
+
+	require.Len(t, suites.Suites, 1)
+	require.Equal(t, 1, suites.Failures)
+	require.Equal(t, "Downloading", suites.Suites[0].Cases[0].Name)
+	require.Contains(t, suites.Suites[0].Cases[0].Failure.Message, "canceled")
+}
+
+func Test_JUnitReport_WithUnfinishedOperation_LeavesItOut(t *testing.T) {
+	report := nesgress.NewJUnitReport("provision")
+	reporter := nesgress.NewLineReporter(io.Discard, report.Dialect(nesgress.PlainDialect()))
+
+	_ = reporter.Start("Installing")
+
+	suites := decodeJUnit(t, report)
+
+	require.Empty(t, suites.Suites)
+}
+
+func Test_JUnitReport_SubscribedToDisplay_RecordsOperations(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+	report := nesgress.NewJUnitReport("provision")
+	display.Subscribe(report.Observe)
+
+	_ = display.Start("Installing")
+	_ = display.Start("Downloading")
+	_ = display.Finish("Downloaded")
+	_ = display.Finish("Installed")
+	require.NoError(t, display.Close())
+
+	path := filepath.Join(t.TempDir(), "report.xml")
+	require.NoError(t, report.Save(path))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(content, &suites))
+	require.Equal(t, "Downloading", suites.Suites[0].Cases[0].Name)
+}
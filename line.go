@@ -0,0 +1,303 @@
+package nesgress
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Dialect writes the lines describing progress events in an output format,
+// such as plain text, JSON or the log commands of a CI system.
+type Dialect interface {
+	// WriteEvent writes the lines describing an event, if it has any.
+	// Events are written one at a time, in the order they happened.
+	WriteEvent(w io.Writer, event Event) error
+}
+
+// LineReporter reports progress as lines appended to a writer, formatted by a dialect.
+// It never redraws anything, so it suits outputs that can't, such as pipes, log files and CI logs.
+type LineReporter struct {
+	output  io.Writer
+	dialect Dialect
+	stack   []*lineOperation
+	mutex   sync.Mutex // protects stack, and serializes the dialect's writes
+	paused  atomic.Bool
+}
+
+// lineOperation is an operation of a LineReporter.
+type lineOperation struct {
+	cancelWork context.CancelFunc // releases the context handed out by StartWithTimeout
+	snapshot   OperationSnapshot
+	done       bool // whether the operation completed by timing out
+}
+
+var (
+	_ ProgressReporter = (*LineReporter)(nil)
+	_ TimeoutStarter   = (*LineReporter)(nil)
+	_ Logger           = (*LineReporter)(nil)
+)
+
+// NewLineReporter creates a reporter writing progress to the given writer in the given dialect.
+func NewLineReporter(output io.Writer, dialect Dialect) *LineReporter {
+	if output == nil {
+		output = os.Stdout
+	}
+
+	return &LineReporter{output: output, dialect: dialect}
+}
+
+// Start begins a new progress operation with the given message.
+func (l *LineReporter) Start(message string) error {
+	_, err := l.start(message, false)
+
+	return err
+}
+
+// StartWithTimeout begins a new progress operation that fails automatically once the timeout passes.
+// The returned context expires together with the operation, with a cause wrapping ErrTimeout.
+// A timed out operation must still be completed with Finish or Fail, which then report nothing.
+func (l *LineReporter) StartWithTimeout(message string, timeout time.Duration) (context.Context, error) {
+	operation, err := l.start(message, false)
+
+	ctx, cancel := context.WithDeadlineCause(
+		context.Background(),
+		operation.snapshot.StartTime.Add(timeout),
+		newTimeoutError(timeout),
+	)
+
+	l.mutex.Lock()
+	operation.cancelWork = cancel
+	l.mutex.Unlock()
+
+	go l.watchDeadline(ctx, operation)
+
+	return ctx, err
+}
+
+// start pushes a new operation onto the stack and reports it.
+func (l *LineReporter) start(message string, persistent bool) (*lineOperation, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	operation := &lineOperation{snapshot: OperationSnapshot{
+		StartTime:  time.Now(),
+		Message:    message,
+		Key:        message,
+		Level:      len(l.stack),
+		Persistent: persistent,
+	}}
+
+	l.stack = append(l.stack, operation)
+
+	return operation, l.write(EventStart, operation, "")
+}
+
+// watchDeadline fails the operation once its context expires, unless it completed first.
+func (l *LineReporter) watchDeadline(ctx context.Context, operation *lineOperation) {
+	<-ctx.Done()
+
+	if !errors.Is(context.Cause(ctx), ErrTimeout) {
+		// Released by completion or Clear
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	operation.done = true
+
+	//nolint:errcheck // Nobody to report writing errors to from the watcher
+	_ = l.end(operation, false, context.Cause(ctx))
+}
+
+// Update modifies the message of the current progress operation.
+func (l *LineReporter) Update(message string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.stack) == 0 {
+		// Updating an inexistent operation is not an error
+		return nil
+	}
+
+	operation := l.stack[len(l.stack)-1]
+	operation.snapshot.Message = message
+
+	return l.write(EventUpdate, operation, "")
+}
+
+// Finish completes the current progress operation successfully.
+func (l *LineReporter) Finish(message string) error {
+	return l.complete(true, nil)
+}
+
+// Fail completes the current progress operation with an error.
+func (l *LineReporter) Fail(message string, err error) error {
+	return l.complete(false, err)
+}
+
+// complete pops the current operation off the stack and reports its outcome.
+func (l *LineReporter) complete(success bool, err error) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.stack) == 0 {
+		return nil
+	}
+
+	operation := l.stack[len(l.stack)-1]
+	l.stack = l.stack[:len(l.stack)-1]
+
+	if operation.cancelWork != nil {
+		operation.cancelWork()
+	}
+
+	// An operation that already timed out has reported its outcome
+	if operation.done {
+		return nil
+	}
+
+	return l.end(operation, success, err)
+}
+
+// end records the outcome of an operation and reports it.
+// Note: This method assumes the caller holds the mutex.
+func (l *LineReporter) end(operation *lineOperation, success bool, err error) error {
+	operation.snapshot.Duration = time.Since(operation.snapshot.StartTime)
+	operation.snapshot.Error = err
+
+	if success {
+		return l.write(EventFinish, operation, "")
+	}
+
+	return l.write(EventFail, operation, "")
+}
+
+// StartPersistent begins a persistent progress operation that shows accomplishments.
+func (l *LineReporter) StartPersistent(message string) error {
+	_, err := l.start(message, true)
+
+	return err
+}
+
+// LogAccomplishment reports an accomplishment of the current operation.
+func (l *LineReporter) LogAccomplishment(message string) error {
+	return l.writeCurrent(EventAccomplishment, message)
+}
+
+// Log reports an informational line beneath the current operation.
+func (l *LineReporter) Log(message string) error {
+	return l.writeCurrent(EventLog, message)
+}
+
+// FinishPersistent completes persistent progress with success.
+func (l *LineReporter) FinishPersistent(message string) error {
+	return l.Finish(message)
+}
+
+// FailPersistent completes persistent progress with failure.
+func (l *LineReporter) FailPersistent(message string, err error) error {
+	return l.Fail(message, err)
+}
+
+// Clear stops all progress operations, reporting those that didn't complete yet as canceled, innermost first.
+func (l *LineReporter) Clear() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var errs []error
+
+	for _, operation := range slices.Backward(l.stack) {
+		if operation.cancelWork != nil {
+			operation.cancelWork()
+		}
+
+		// Operations that timed out already reported their outcome
+		if !operation.done {
+			operation.snapshot.Duration = time.Since(operation.snapshot.StartTime)
+			errs = append(errs, l.write(EventCancel, operation, ""))
+		}
+	}
+
+	l.stack = nil
+
+	return errors.Join(errs...)
+}
+
+// Pause marks the reporter as paused. Lines don't need to make way for prompts, so nothing else changes.
+func (l *LineReporter) Pause() error {
+	l.paused.Store(true)
+
+	return l.writeCurrent(EventPause, "")
+}
+
+// Resume marks the reporter as no longer paused.
+func (l *LineReporter) Resume() error {
+	l.paused.Store(false)
+
+	return l.writeCurrent(EventResume, "")
+}
+
+// IsActive returns true if there are any operations that didn't complete yet.
+func (l *LineReporter) IsActive() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, operation := range l.stack {
+		if !operation.done {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPaused returns whether the reporter is currently paused.
+func (l *LineReporter) IsPaused() bool {
+	return l.paused.Load()
+}
+
+// Close stops all progress operations like Clear.
+func (l *LineReporter) Close() error {
+	return l.Clear()
+}
+
+// writeCurrent reports an event about the current operation, if there is one.
+func (l *LineReporter) writeCurrent(eventType EventType, message string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var operation *lineOperation
+	if len(l.stack) > 0 {
+		operation = l.stack[len(l.stack)-1]
+	}
+
+	return l.write(eventType, operation, message)
+}
+
+// write hands an event about the given operation, which may be nil, to the dialect.
+// Note: This method assumes the caller holds the mutex.
+func (l *LineReporter) write(eventType EventType, operation *lineOperation, message string) error {
+	event := Event{
+		Type:    eventType,
+		Time:    time.Now(),
+		Message: message,
+	}
+
+	if operation != nil {
+		snapshot := operation.snapshot
+		event.Operation = &snapshot
+
+		// Operations are nested in the order they are stacked
+		for _, ancestor := range l.stack[:min(operation.snapshot.Level, len(l.stack))] {
+			event.Ancestors = append(event.Ancestors, ancestor.snapshot)
+		}
+	}
+
+	return l.dialect.WriteEvent(l.output, event)
+}
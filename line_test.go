@@ -0,0 +1,97 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_LineReporter_WithNestedOperations_WritesIndentedLines(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.PlainDialect())
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading zlib")
+	_ = reporter.Finish("Downloaded zlib")
+	_ = reporter.Finish("Installed")
+
+	require.Equal(t, "Installing...\n  Downloading zlib...\n  [ok] Downloading zlib\n[ok] Installing\n", buf.String())
+}
+
+func Test_LineReporter_WithFailure_WritesError(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.PlainDialect())
+
+	_ = reporter.Start("Uploading")
+	_ = reporter.Fail("Upload failed", fmt.Errorf("upload: %w", errors.New("connection refused")))
+
+	require.Contains(t, buf.String(), "[FAIL] Uploading\n  Error: upload\n    -> connection refused\n")
+}
+
+func Test_LineReporter_WithAccomplishmentsAndLogs_IndentsThemUnderOperation(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.PlainDialect())
+
+	_ = reporter.StartPersistent("Deploying")
+	_ = reporter.LogAccomplishment("Built container")
+	_ = reporter.Log("Using registry mirror")
+	_ = reporter.Update("Deploying to staging")
+	_ = reporter.FinishPersistent("Deployed")
+
+	require.Equal(t,
+		"Deploying...\n  [ok] Built container\n  Using registry mirror\nDeploying to staging...\n[ok] Deploying to staging\n",
+		buf.String(),
+	)
+}
+
+func Test_LineReporter_WithTimeout_FailsOperationOnce(t *testing.T) {
+	var buf lockedBuffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.PlainDialect())
+
+	ctx, err := reporter.StartWithTimeout("Waiting for daemon", 20*time.Millisecond)
+	require.NoError(t, err)
+
+	<-ctx.Done()
+	require.ErrorIs(t, context.Cause(ctx), nesgress.ErrTimeout)
+
+	require.Eventually(t, func() bool {
+		return !reporter.IsActive()
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, reporter.Fail("Daemon unavailable", context.Cause(ctx)))
+	require.Equal(t, 1, bytes.Count([]byte(buf.String()), []byte("[FAIL] Waiting for daemon")))
+}
+
+func Test_LineReporter_AfterClear_IsInactive(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.PlainDialect())
+
+	_ = reporter.Start("Installing")
+	require.True(t, reporter.IsActive())
+
+	require.NoError(t, reporter.Close())
+	require.False(t, reporter.IsActive())
+	require.NoError(t, reporter.Finish("Installed"))
+	require.Equal(t, "Installing...\n[canceled] Installing\n", buf.String())
+}
+
+func Test_LineReporter_WhenCleared_CancelsRunningOperationsInnermostFirst(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := nesgress.NewLineReporter(&buf, nesgress.PlainDialect())
+
+	_ = reporter.Start("Installing")
+	_ = reporter.Start("Downloading zlib")
+
+	require.NoError(t, reporter.Clear())
+	require.Equal(t,
+		"Installing...\n  Downloading zlib...\n  [canceled] Downloading zlib\n[canceled] Installing\n",
+		buf.String(),
+	)
+}
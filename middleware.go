@@ -0,0 +1,511 @@
+package nesgress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// transformReporter rewrites the messages of every call before forwarding it.
+type transformReporter struct {
+	ProgressReporter
+
+	transform func(message string) string
+}
+
+// Transform creates a reporter that rewrites the messages of operations, accomplishments and log lines
+// with the given function before forwarding them. Errors are forwarded unchanged.
+func Transform(reporter ProgressReporter, transform func(message string) string) ProgressReporter {
+	return &transformReporter{ProgressReporter: reporter, transform: transform}
+}
+
+// WithPrefix creates a reporter that prefixes the messages of operations, accomplishments and log lines,
+// e.g. to tell apart the progress of several nodes sharing a display.
+func WithPrefix(reporter ProgressReporter, prefix string) ProgressReporter {
+	return Transform(reporter, func(message string) string {
+		return prefix + message
+	})
+}
+
+// Start begins a new progress operation with the transformed message.
+func (t *transformReporter) Start(message string) error {
+	return t.ProgressReporter.Start(t.transform(message))
+}
+
+// StartWithTimeout begins a new progress operation with a timeout and the transformed message.
+func (t *transformReporter) StartWithTimeout(message string, timeout time.Duration) (context.Context, error) {
+	return startWithTimeout(t.ProgressReporter, t.transform(message), timeout)
+}
+
+// Update modifies the current progress operation with the transformed message.
+func (t *transformReporter) Update(message string) error {
+	return t.ProgressReporter.Update(t.transform(message))
+}
+
+// Finish completes the current progress operation with the transformed message.
+func (t *transformReporter) Finish(message string) error {
+	return t.ProgressReporter.Finish(t.transform(message))
+}
+
+// Fail completes the current progress operation with an error and the transformed message.
+func (t *transformReporter) Fail(message string, err error) error {
+	return t.ProgressReporter.Fail(t.transform(message), err)
+}
+
+// StartPersistent begins a persistent progress operation with the transformed message.
+func (t *transformReporter) StartPersistent(message string) error {
+	return t.ProgressReporter.StartPersistent(t.transform(message))
+}
+
+// LogAccomplishment logs the transformed accomplishment.
+func (t *transformReporter) LogAccomplishment(message string) error {
+	return t.ProgressReporter.LogAccomplishment(t.transform(message))
+}
+
+// Log prints the transformed informational line.
+func (t *transformReporter) Log(message string) error {
+	return logLine(t.ProgressReporter, t.transform(message))
+}
+
+// FinishPersistent completes persistent progress with the transformed message.
+func (t *transformReporter) FinishPersistent(message string) error {
+	return t.ProgressReporter.FinishPersistent(t.transform(message))
+}
+
+// FailPersistent completes persistent progress with an error and the transformed message.
+func (t *transformReporter) FailPersistent(message string, err error) error {
+	return t.ProgressReporter.FailPersistent(t.transform(message), err)
+}
+
+// filteredOperation is an operation started through a filter.
+type filteredOperation struct {
+	cancel  context.CancelFunc // releases the context of a dropped operation started with a timeout
+	message string
+	dropped bool
+}
+
+// filterReporter drops the operations its predicate rejects, along with everything happening inside them.
+type filterReporter struct {
+	ProgressReporter
+
+	keep       func(path []string) bool
+	operations []filteredOperation
+	mutex      sync.Mutex
+}
+
+// Filter creates a reporter that forwards only the operations the given predicate keeps.
+//
+// The predicate receives the path of an operation being started: the messages its enclosing operations
+// and itself were started with, outermost first, so len(path)-1 is its nesting depth.
+// Dropped operations are dropped along with their nested operations, accomplishments and log lines.
+func Filter(reporter ProgressReporter, keep func(path []string) bool) ProgressReporter {
+	return &filterReporter{ProgressReporter: reporter, keep: keep}
+}
+
+// push records a new operation, deciding whether it's dropped.
+// Note: This method assumes the caller holds the filter's mutex.
+func (f *filterReporter) push(message string) *filteredOperation {
+	dropped := f.insideDropped()
+
+	if !dropped {
+		path := make([]string, 0, len(f.operations)+1)
+		for _, operation := range f.operations {
+			path = append(path, operation.message)
+		}
+
+		dropped = !f.keep(append(path, message))
+	}
+
+	f.operations = append(f.operations, filteredOperation{message: message, dropped: dropped})
+
+	return &f.operations[len(f.operations)-1]
+}
+
+// pop removes the current operation and reports whether it was dropped.
+// Note: This method assumes the caller holds the filter's mutex.
+func (f *filterReporter) pop() bool {
+	if len(f.operations) == 0 {
+		return false
+	}
+
+	operation := f.operations[len(f.operations)-1]
+	f.operations = f.operations[:len(f.operations)-1]
+
+	if operation.cancel != nil {
+		operation.cancel()
+	}
+
+	return operation.dropped
+}
+
+// insideDropped reports whether the current operation is dropped.
+// Note: This method assumes the caller holds the filter's mutex.
+func (f *filterReporter) insideDropped() bool {
+	return len(f.operations) > 0 && f.operations[len(f.operations)-1].dropped
+}
+
+// reset forgets all operations, releasing the contexts of dropped ones.
+// Note: This method assumes the caller holds the filter's mutex.
+func (f *filterReporter) reset() {
+	for len(f.operations) > 0 {
+		f.pop()
+	}
+}
+
+// Start begins a new progress operation if it's kept.
+func (f *filterReporter) Start(message string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.push(message).dropped {
+		return nil
+	}
+
+	return f.ProgressReporter.Start(message)
+}
+
+// StartWithTimeout forwards the operation if it's kept.
+// Dropped operations still get a context that expires once the timeout passes.
+func (f *filterReporter) StartWithTimeout(message string, timeout time.Duration) (context.Context, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	operation := f.push(message)
+	if !operation.dropped {
+		return startWithTimeout(f.ProgressReporter, message, timeout)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), timeout, newTimeoutError(timeout))
+	operation.cancel = cancel
+
+	return ctx, nil
+}
+
+// StartPersistent begins a persistent progress operation if it's kept.
+func (f *filterReporter) StartPersistent(message string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.push(message).dropped {
+		return nil
+	}
+
+	return f.ProgressReporter.StartPersistent(message)
+}
+
+// Update modifies the current progress operation unless it's dropped.
+func (f *filterReporter) Update(message string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.insideDropped() {
+		return nil
+	}
+
+	return f.ProgressReporter.Update(message)
+}
+
+// Finish completes the current progress operation unless it's dropped.
+func (f *filterReporter) Finish(message string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.pop() {
+		return nil
+	}
+
+	return f.ProgressReporter.Finish(message)
+}
+
+// Fail completes the current progress operation with an error unless it's dropped.
+func (f *filterReporter) Fail(message string, err error) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.pop() {
+		return nil
+	}
+
+	return f.ProgressReporter.Fail(message, err)
+}
+
+// FinishPersistent completes persistent progress unless it's dropped.
+func (f *filterReporter) FinishPersistent(message string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.pop() {
+		return nil
+	}
+
+	return f.ProgressReporter.FinishPersistent(message)
+}
+
+// FailPersistent completes persistent progress with an error unless it's dropped.
+func (f *filterReporter) FailPersistent(message string, err error) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.pop() {
+		return nil
+	}
+
+	return f.ProgressReporter.FailPersistent(message, err)
+}
+
+// LogAccomplishment logs an accomplishment unless the current operation is dropped.
+func (f *filterReporter) LogAccomplishment(message string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.insideDropped() {
+		return nil
+	}
+
+	return f.ProgressReporter.LogAccomplishment(message)
+}
+
+// Log prints an informational line unless the current operation is dropped.
+func (f *filterReporter) Log(message string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.insideDropped() {
+		return nil
+	}
+
+	return logLine(f.ProgressReporter, message)
+}
+
+// Clear stops all progress operations and forgets the dropped ones.
+func (f *filterReporter) Clear() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.reset()
+
+	return f.ProgressReporter.Clear()
+}
+
+// Close closes the reporter and forgets the dropped operations.
+func (f *filterReporter) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.reset()
+
+	return f.ProgressReporter.Close()
+}
+
+// rateLimitedReporter holds back bursts of updates and accomplishments, forwarding at most one of each per interval.
+type rateLimitedReporter struct {
+	ProgressReporter
+
+	flushTimer            *time.Timer
+	lastUpdate            time.Time
+	lastAccomplishment    time.Time
+	pendingUpdate         string
+	pendingAccomplishment string
+	interval              time.Duration
+	heldAccomplishments   int
+	hasPendingUpdate      bool
+	mutex                 sync.Mutex
+}
+
+// RateLimit creates a reporter that forwards at most one update and one accomplishment per interval.
+//
+// Updates held back within an interval are coalesced into the latest one.
+// Accomplishments held back are coalesced into the latest one, followed by the number of others, e.g. "(and 3 more)".
+// Held back calls are forwarded once the interval passes, or before any other call, so the order of calls is kept.
+func RateLimit(reporter ProgressReporter, interval time.Duration) ProgressReporter {
+	return &rateLimitedReporter{ProgressReporter: reporter, interval: interval}
+}
+
+// Update modifies the current progress operation, or holds the update back until the interval passes.
+func (r *rateLimitedReporter) Update(message string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.hasPendingUpdate && time.Since(r.lastUpdate) >= r.interval {
+		r.lastUpdate = time.Now()
+		return r.ProgressReporter.Update(message)
+	}
+
+	r.pendingUpdate = message
+	r.hasPendingUpdate = true
+	r.scheduleFlush(r.lastUpdate)
+
+	return nil
+}
+
+// LogAccomplishment logs an accomplishment, or holds it back until the interval passes.
+func (r *rateLimitedReporter) LogAccomplishment(message string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.heldAccomplishments == 0 && time.Since(r.lastAccomplishment) >= r.interval {
+		r.lastAccomplishment = time.Now()
+		return r.ProgressReporter.LogAccomplishment(message)
+	}
+
+	r.pendingAccomplishment = message
+	r.heldAccomplishments++
+	r.scheduleFlush(r.lastAccomplishment)
+
+	return nil
+}
+
+// scheduleFlush arranges for held back calls to be forwarded one interval after the given time.
+// Note: This method assumes the caller holds the reporter's mutex.
+func (r *rateLimitedReporter) scheduleFlush(last time.Time) {
+	if r.flushTimer != nil {
+		return
+	}
+
+	r.flushTimer = time.AfterFunc(time.Until(last.Add(r.interval)), func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		//nolint:errcheck // Nobody to report display errors to from the timer
+		_ = r.flush()
+	})
+}
+
+// flush forwards the calls held back so far.
+// Note: This method assumes the caller holds the reporter's mutex.
+func (r *rateLimitedReporter) flush() error {
+	if r.flushTimer != nil {
+		r.flushTimer.Stop()
+		r.flushTimer = nil
+	}
+
+	var errs []error
+
+	if r.heldAccomplishments > 0 {
+		message := r.pendingAccomplishment
+		if r.heldAccomplishments > 1 {
+			message = fmt.Sprintf("%s (and %d more)", message, r.heldAccomplishments-1)
+		}
+
+		r.heldAccomplishments = 0
+		r.lastAccomplishment = time.Now()
+
+		errs = append(errs, r.ProgressReporter.LogAccomplishment(message))
+	}
+
+	if r.hasPendingUpdate {
+		r.hasPendingUpdate = false
+		r.lastUpdate = time.Now()
+
+		errs = append(errs, r.ProgressReporter.Update(r.pendingUpdate))
+	}
+
+	return errors.Join(errs...)
+}
+
+// discard drops the calls held back so far.
+// Note: This method assumes the caller holds the reporter's mutex.
+func (r *rateLimitedReporter) discard() {
+	if r.flushTimer != nil {
+		r.flushTimer.Stop()
+		r.flushTimer = nil
+	}
+
+	r.hasPendingUpdate = false
+	r.heldAccomplishments = 0
+}
+
+// flushThen forwards the calls held back so far before the given call.
+func (r *rateLimitedReporter) flushThen(call func() error) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	err := r.flush()
+
+	return errors.Join(err, call())
+}
+
+// Start begins a new progress operation after forwarding held back calls.
+func (r *rateLimitedReporter) Start(message string) error {
+	return r.flushThen(func() error {
+		return r.ProgressReporter.Start(message)
+	})
+}
+
+// StartWithTimeout begins a new progress operation with a timeout after forwarding held back calls.
+func (r *rateLimitedReporter) StartWithTimeout(message string, timeout time.Duration) (context.Context, error) {
+	var ctx context.Context
+
+	err := r.flushThen(func() error {
+		var err error
+		ctx, err = startWithTimeout(r.ProgressReporter, message, timeout)
+
+		return err
+	})
+
+	return ctx, err
+}
+
+// Finish completes the current progress operation after forwarding held back calls.
+func (r *rateLimitedReporter) Finish(message string) error {
+	return r.flushThen(func() error {
+		return r.ProgressReporter.Finish(message)
+	})
+}
+
+// Fail completes the current progress operation with an error after forwarding held back calls.
+func (r *rateLimitedReporter) Fail(message string, err error) error {
+	return r.flushThen(func() error {
+		return r.ProgressReporter.Fail(message, err)
+	})
+}
+
+// StartPersistent begins a persistent progress operation after forwarding held back calls.
+func (r *rateLimitedReporter) StartPersistent(message string) error {
+	return r.flushThen(func() error {
+		return r.ProgressReporter.StartPersistent(message)
+	})
+}
+
+// Log prints an informational line after forwarding held back calls.
+func (r *rateLimitedReporter) Log(message string) error {
+	return r.flushThen(func() error {
+		return logLine(r.ProgressReporter, message)
+	})
+}
+
+// FinishPersistent completes persistent progress after forwarding held back calls.
+func (r *rateLimitedReporter) FinishPersistent(message string) error {
+	return r.flushThen(func() error {
+		return r.ProgressReporter.FinishPersistent(message)
+	})
+}
+
+// FailPersistent completes persistent progress with an error after forwarding held back calls.
+func (r *rateLimitedReporter) FailPersistent(message string, err error) error {
+	return r.flushThen(func() error {
+		return r.ProgressReporter.FailPersistent(message, err)
+	})
+}
+
+// Clear stops all progress operations, discarding held back calls instead of forwarding them.
+func (r *rateLimitedReporter) Clear() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.discard()
+
+	return r.ProgressReporter.Clear()
+}
+
+// Pause pauses the reporter after forwarding held back calls.
+func (r *rateLimitedReporter) Pause() error {
+	return r.flushThen(r.ProgressReporter.Pause)
+}
+
+// Close closes the reporter after forwarding held back calls.
+func (r *rateLimitedReporter) Close() error {
+	return r.flushThen(r.ProgressReporter.Close)
+}
@@ -0,0 +1,194 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_Transform_WithMessageFunction_RewritesEveryMessage(t *testing.T) {
+	recorder := &recordingReporter{}
+	reporter := nesgress.Transform(recorder, strings.ToUpper)
+
+	require.NoError(t, reporter.Start("building"))
+	require.NoError(t, reporter.Update("linking"))
+	require.NoError(t, reporter.LogAccomplishment("linked"))
+	require.NoError(t, reporter.Finish("built"))
+
+	require.Equal(t, []string{
+		"start: BUILDING",
+		"update: LINKING",
+		"accomplishment: LINKED",
+		"finish: BUILT",
+	}, recorder.calls)
+}
+
+func Test_WithPrefix_WithNestedOperations_PrefixesEachMessage(t *testing.T) {
+	var buf bytes.Buffer
+
+	display := nesgress.NewProgressDisplay(&buf)
+	reporter := nesgress.WithPrefix(display, "[node-3] ")
+
+	logger, ok := reporter.(nesgress.Logger)
+	require.True(t, ok)
+
+	require.NoError(t, reporter.Start("Deploying"))
+	require.NoError(t, logger.Log("pulling image"))
+	require.NoError(t, reporter.Finish("Deploying"))
+	require.NoError(t, reporter.Close())
+
+	output := display.GetOutputSafely()
+	require.Contains(t, output, "[node-3] pulling image")
+	require.Contains(t, output, "✓ [node-3] Deploying")
+}
+
+func Test_Filter_ByDepth_DropsNestedOperationsAndTheirOutput(t *testing.T) {
+	recorder := &recordingReporter{}
+	reporter := nesgress.Filter(recorder, func(path []string) bool {
+		return len(path) <= 1
+	})
+
+	require.NoError(t, reporter.Start("Installing"))
+	require.NoError(t, reporter.Start("Resolving"))
+	require.NoError(t, reporter.Update("Resolving 3/10"))
+	require.NoError(t, reporter.LogAccomplishment("resolved"))
+	require.NoError(t, reporter.Start("Downloading"))
+	require.NoError(t, reporter.Finish("Downloading"))
+	require.NoError(t, reporter.Finish("Resolving"))
+	require.NoError(t, reporter.Update("Installing 1/1"))
+	require.NoError(t, reporter.Finish("Installed"))
+
+	require.Equal(t, []string{
+		"start: Installing",
+		"update: Installing 1/1",
+		"finish: Installed",
+	}, recorder.calls)
+}
+
+func Test_Filter_ByPath_DropsMatchingSubtreeOnly(t *testing.T) {
+	recorder := &recordingReporter{}
+	reporter := nesgress.Filter(recorder, func(path []string) bool {
+		return path[len(path)-1] != "Indexing"
+	})
+
+	require.NoError(t, reporter.Start("Syncing"))
+	require.NoError(t, reporter.Start("Indexing"))
+	require.NoError(t, reporter.Start("Hashing"))
+	require.NoError(t, reporter.Finish("Hashing"))
+	require.NoError(t, reporter.Finish("Indexing"))
+	require.NoError(t, reporter.Start("Uploading"))
+	require.NoError(t, reporter.Finish("Uploading"))
+	require.NoError(t, reporter.Finish("Syncing"))
+
+	require.Equal(t, []string{
+		"start: Syncing",
+		"start: Uploading",
+		"finish: Uploading",
+		"finish: Syncing",
+	}, recorder.calls)
+}
+
+func Test_Filter_WithDroppedTimedOperation_ReleasesContextOnFinish(t *testing.T) {
+	reporter := nesgress.Filter(&recordingReporter{}, func(path []string) bool {
+		return false
+	})
+
+	starter, ok := reporter.(nesgress.TimeoutStarter)
+	require.True(t, ok)
+
+	ctx, err := starter.StartWithTimeout("Fetching", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Err())
+
+	require.NoError(t, reporter.Finish("Fetching"))
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func Test_RateLimit_WithBurstOfUpdates_ForwardsFirstAndLatest(t *testing.T) {
+	recorder := &recordingReporter{}
+	reporter := nesgress.RateLimit(recorder, time.Hour)
+
+	require.NoError(t, reporter.Start("Downloading"))
+
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, reporter.Update("Downloading "+strings.Repeat("#", i)))
+	}
+
+	require.NoError(t, reporter.Finish("Downloaded"))
+
+	require.Equal(t, []string{
+		"start: Downloading",
+		"update: Downloading #",
+		"update: Downloading ##########",
+		"finish: Downloaded",
+	}, recorder.calls)
+}
+
+func Test_RateLimit_WithBurstOfAccomplishments_CoalescesHeldBackOnes(t *testing.T) {
+	recorder := &recordingReporter{}
+	reporter := nesgress.RateLimit(recorder, time.Hour)
+
+	require.NoError(t, reporter.Start("Installing"))
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, reporter.LogAccomplishment("Installed "+name))
+	}
+
+	require.NoError(t, reporter.Finish("Installed"))
+
+	require.Equal(t, []string{
+		"start: Installing",
+		"accomplishment: Installed a",
+		"accomplishment: Installed e (and 3 more)",
+		"finish: Installed",
+	}, recorder.calls)
+}
+
+func Test_RateLimit_WhenCleared_DiscardsHeldBackCalls(t *testing.T) {
+	recorder := &recordingReporter{}
+	reporter := nesgress.RateLimit(recorder, time.Hour)
+
+	require.NoError(t, reporter.Start("Installing"))
+	require.NoError(t, reporter.Update("Installing a"))
+	require.NoError(t, reporter.Update("Installing b"))
+	require.NoError(t, reporter.LogAccomplishment("Installed a"))
+	require.NoError(t, reporter.LogAccomplishment("Installed b"))
+	require.NoError(t, reporter.Clear())
+	require.NoError(t, reporter.Start("Configuring"))
+
+	require.Equal(t, []string{
+		"start: Installing",
+		"update: Installing a",
+		"accomplishment: Installed a",
+		"start: Configuring",
+	}, recorder.calls)
+}
+
+func Test_RateLimit_AfterInterval_ForwardsHeldBackCalls(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+
+	display := nesgress.NewProgressDisplay(&buf)
+	reporter := nesgress.RateLimit(display, 50*time.Millisecond)
+
+	require.NoError(t, reporter.Start("Installing"))
+	require.NoError(t, reporter.LogAccomplishment("Installed a"))
+	require.NoError(t, reporter.LogAccomplishment("Installed b"))
+	require.NoError(t, reporter.LogAccomplishment("Installed c"))
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(display.GetOutputSafely(), "Installed c (and 1 more)")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, reporter.Finish("Installed"))
+	require.NoError(t, reporter.Close())
+}
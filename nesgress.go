@@ -1,18 +1,18 @@
 package nesgress
 
 import (
-	"bytes"
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/charmbracelet/huh/spinner"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -20,24 +20,51 @@ import (
 const (
 	clearLine  = "\033[K"    // Clear line from cursor to end
 	showCursor = "\033[?25h" // Show cursor
+	hideCursor = "\033[?25l" // Hide cursor
 )
 
+// accomplishmentIndentUnit is the indentation added for each level of persistent operations.
+const accomplishmentIndentUnit = "   "
+
 // Duration constants for display formatting.
 const (
 	durationDisplayThreshold = 100 * time.Millisecond // Minimum duration to show timing info
 	durationRoundPrecision   = 10 * time.Millisecond  // Round displayed durations to this precision
-	spinnerTickInterval      = 50 * time.Millisecond  // How often spinner checks for updates
 )
 
 // ProgressOperation represents an active progress operation.
 type ProgressOperation struct {
-	StartTime  time.Time
-	Error      error
-	CancelFunc context.CancelFunc
-	Message    string
-	Level      int
-	done       atomic.Int32
-	Success    bool
+	StartTime time.Time
+	Error     error
+	parent    *ProgressOperation
+	// CancelFunc used to stop the operation's spinner.
+	//
+	// Deprecated: Operations no longer run a spinner of their own, as a single render loop draws them all.
+	// It's set to a function doing nothing, so callers still calling it keep working.
+	CancelFunc     context.CancelFunc
+	cancelWork     context.CancelFunc // releases the context handed out by StartWithTimeout
+	Message        string
+	key            string               // stable identity in the display's history
+	children       []*ProgressOperation // completed children held back for tree output
+	outcome        operationOutcome     // copied on completion, so rendering it doesn't race with updates
+	timeout        time.Duration
+	Level          int
+	duration       time.Duration
+	lastProgress   atomic.Int64 // unix nanoseconds of the last sign of progress
+	stallThreshold atomic.Int64 // per-operation override of the display's stall threshold
+	done           atomic.Int32
+	stallReported  atomic.Bool // whether the current stall was reported to the stall handler
+	Success        bool
+	Persistent     bool
+	rendered       bool // whether the operation's tree was rendered, guarded by the display's stackMutex
+}
+
+// operationOutcome is the state of an operation at the time it completed.
+type operationOutcome struct {
+	err      error
+	message  string
+	duration time.Duration
+	success  bool
 }
 
 // IsDone returns whether this operation is completed.
@@ -50,6 +77,31 @@ func (op *ProgressOperation) SetDone() {
 	op.done.Store(1)
 }
 
+// persistentAncestor returns the closest enclosing persistent operation, or nil if there is none.
+func (op *ProgressOperation) persistentAncestor() *ProgressOperation {
+	for ancestor := op.parent; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor.Persistent {
+			return ancestor
+		}
+	}
+
+	return nil
+}
+
+// accomplishmentIndent returns the indentation of lines logged under this persistent operation.
+// Each enclosing persistent operation adds one level, so nested accomplishments line up under their owner.
+func (op *ProgressOperation) accomplishmentIndent() string {
+	depth := 0
+
+	for current := op; current != nil; current = current.parent {
+		if current.Persistent {
+			depth++
+		}
+	}
+
+	return strings.Repeat(accomplishmentIndentUnit, depth)
+}
+
 // Resumable defines the interface for pausing and resuming progress operations.
 type Resumable interface {
 	// Pause temporarily stops all spinner operations for interactive commands.
@@ -90,202 +142,252 @@ type ProgressReporter interface {
 	Clear() error
 }
 
+// TimeoutStarter is implemented by reporters that can start operations failing automatically once a timeout passes,
+// such as ProgressDisplay. Callers type-assert a ProgressReporter to it.
+type TimeoutStarter interface {
+	// StartWithTimeout begins a new progress operation that fails automatically once the timeout passes
+	StartWithTimeout(message string, timeout time.Duration) (context.Context, error)
+}
+
+// Logger is implemented by reporters that can print informational lines beneath the current operation,
+// such as ProgressDisplay. Callers type-assert a ProgressReporter to it.
+type Logger interface {
+	// Log prints a dimmed informational line beneath the current operation
+	Log(message string) error
+}
+
+// startWithTimeout begins an operation with a timeout on reporters that support it.
+// Other reporters begin a regular operation, along with a context that never expires.
+func startWithTimeout(reporter ProgressReporter, message string, timeout time.Duration) (context.Context, error) {
+	if starter, ok := reporter.(TimeoutStarter); ok {
+		return starter.StartWithTimeout(message, timeout)
+	}
+
+	return context.Background(), reporter.Start(message)
+}
+
+// logLine prints an informational line on reporters that support it, and nothing on others.
+func logLine(reporter ProgressReporter, message string) error {
+	if logger, ok := reporter.(Logger); ok {
+		return logger.Log(message)
+	}
+
+	return nil
+}
+
 // ProgressDisplay provides hierarchical progress reporting with npm-style output.
 type ProgressDisplay struct {
-	output              io.Writer
-	rawOutput           io.Writer        // original output for direct access when needed
+	lastAnnouncement    time.Time        // when a line was last written in accessible mode, protected by renderMutex
+	output              io.Writer        // live UI (spinners), redrawn in place
+	rawOutput           io.Writer        // original live output for direct access when needed
+	resultOutput        io.Writer        // permanent success lines
+	errorOutput         io.Writer        // permanent failure lines
 	safeBuffer          *safeBytesBuffer // for thread-safe buffer access when using bytes.Buffer
-	activeSpinner       *ProgressOperation
+	history             *History         // durations of previous runs, for estimating remaining time
+	events              *eventDispatcher // delivers lifecycle events to observers
+	stallHandler        StallHandler     // called once whenever an operation stalls
+	elapsedFormat       ElapsedFormatter // formats the running time shown next to the spinner
+	embedded            *teaOutbox       // receives all output instead of the streams once embedded in a TeaModel
+	glyphs              *glyphSet        // symbols drawn by the display
+	terminal            *os.File         // terminal whose width the live line follows, nil if the width is fixed
+	promptInput         io.Reader        // replies to prompts
+	promptReader        *bufio.Reader    // buffers promptInput across prompts
 	progressStack       []*ProgressOperation
-	spinnerWaitGroup    sync.WaitGroup // tracks active spinner goroutines
-	stackMutex          sync.RWMutex   // protects progressStack and activeSpinner
-	pauseMutex          sync.Mutex     // protects pause/resume operations
-	operationInProgress atomic.Int32   // atomic counter
-	cursorHidden        atomic.Int32   // atomic flag for cursor state
-	paused              atomic.Int32   // atomic flag for paused state
-	persistentMode      bool           // whether we're in persistent mode
+	width               atomic.Int64  // display width of the live line in cells, zero if unlimited
+	stackMutex          sync.RWMutex  // protects progressStack
+	renderMutex         sync.Mutex    // serializes frames with permanent lines, taken before stackMutex
+	pauseMutex          sync.Mutex    // protects pause/resume operations
+	promptMutex         sync.Mutex    // serializes prompts, so one at a time has the terminal
+	stallThreshold      time.Duration // idle time after which operations are considered stalled
+	elapsedThreshold    time.Duration // running time below which the elapsed timer stays hidden
+	startDelay          time.Duration // running time before the spinner of an operation is drawn
+	frame               int           // index of the next spinner frame, protected by renderMutex
+	operationInProgress atomic.Int32  // atomic counter
+	cursorHidden        atomic.Int32  // atomic flag for cursor state
+	paused              atomic.Int32  // atomic flag for paused state
+	rendering           bool          // whether the render loop runs, protected by renderMutex
+	frameDrawn          bool          // whether the live line shows a frame, protected by renderMutex
+	treeOutput          bool          // whether completions are rendered as a tree
+	errorDetails        bool          // whether %+v details of errors are rendered
+	showElapsed         bool          // whether the running time is shown next to the spinner
+	elapsedAncestors    bool          // whether ancestors show their running time as well
+	accessible          bool          // whether progress is announced in sentences instead of drawn
 }
 
-var _ ProgressReporter = (*ProgressDisplay)(nil)
+var (
+	_ ProgressReporter = (*ProgressDisplay)(nil)
+	_ TimeoutStarter   = (*ProgressDisplay)(nil)
+	_ Logger           = (*ProgressDisplay)(nil)
+)
 
 // NewProgressDisplay creates a new hierarchical progress display.
-func NewProgressDisplay(output io.Writer) *ProgressDisplay {
+func NewProgressDisplay(output io.Writer, opts ...Option) *ProgressDisplay {
 	if output == nil {
 		output = os.Stdout
 	}
 
-	var outputWriter io.Writer
+	pd := &ProgressDisplay{
+		rawOutput:  output,
+		events:     newEventDispatcher(),
+		accessible: accessibleFromEnvironment(),
+	}
 
-	var safeBuffer *safeBytesBuffer
+	for _, opt := range opts {
+		opt(pd)
+	}
 
-	// Special handling for *bytes.Buffer to ensure thread safety
+	if pd.accessible {
+		// Screen readers can't follow a tree printed all at once at the end
+		pd.glyphs = &accessibleGlyphs
+		pd.treeOutput = false
+	}
 
-	if buf, ok := output.(*bytes.Buffer); ok {
-		safeBuffer = &safeBytesBuffer{buf: buf}
-		outputWriter = safeBuffer
-	} else {
-		outputWriter = &synchronizedWriter{writer: output}
+	if pd.resultOutput == nil {
+		pd.resultOutput = output
 	}
 
-	pd := &ProgressDisplay{
-		output:     outputWriter,
-		safeBuffer: safeBuffer,
-		rawOutput:  output,
+	if pd.errorOutput == nil {
+		// Write to stderr for errors, unless given a dedicated writer
+		pd.errorOutput = output
+		if output == os.Stdout {
+			pd.errorOutput = os.Stderr
+		}
 	}
 
-	// Ensure cursor is restored on program exit
-	pd.setupCleanup()
+	if pd.glyphs == nil {
+		pd.glyphs = detectGlyphs(pd.rawOutput)
+	}
 
-	return pd
-}
+	if pd.promptInput == nil {
+		pd.promptInput = os.Stdin
+	}
 
-// Start begins a new progress operation with the given message.
-func (p *ProgressDisplay) Start(message string) error {
-	p.stackMutex.Lock()
-	level := len(p.progressStack)
+	pd.promptReader = bufio.NewReader(pd.promptInput)
 
-	// Stop any currently active spinner
-	if p.activeSpinner != nil && p.activeSpinner.CancelFunc != nil {
-		p.activeSpinner.CancelFunc()
-	}
+	// Streams sharing an underlying writer also share its synchronization
+	var writers synchronizedWriters
 
-	// Create context for this operation
-	ctx, cancel := context.WithCancel(context.Background())
+	pd.output = writers.wrap(pd.rawOutput)
+	pd.resultOutput = writers.wrap(pd.resultOutput)
+	pd.errorOutput = writers.wrap(pd.errorOutput)
 
-	operation := &ProgressOperation{
-		Message:    message,
-		StartTime:  time.Now(),
-		Level:      level,
-		CancelFunc: cancel,
+	// Keep thread-safe read access when the live output is a bytes.Buffer
+	if safeBuffer, ok := pd.output.(*safeBytesBuffer); ok {
+		pd.safeBuffer = safeBuffer
 	}
-	p.progressStack = append(p.progressStack, operation)
-	p.activeSpinner = operation
 
-	// Create contextual message showing hierarchy
-	displayMessage := p.buildContextualMessage()
-	p.stackMutex.Unlock()
+	// Clip the live line to the terminal, which may be resized meanwhile
+	pd.trackWidth()
 
-	// Increment operation counter
-	p.operationInProgress.Add(1)
+	// Ensure cursor is restored on program exit
+	pd.setupCleanup()
 
-	// Start spinner in background
-	p.spinnerWaitGroup.Add(1)
+	return pd
+}
 
-	go p.runSpinner(ctx, operation, displayMessage)
+// Start begins a new progress operation with the given message.
+func (p *ProgressDisplay) Start(message string) error {
+	p.start(&ProgressOperation{Message: message})
 
 	return nil
 }
 
-// Update modifies the message of the current progress operation.
-func (p *ProgressDisplay) Update(message string) error {
-	p.stackMutex.Lock()
-	defer p.stackMutex.Unlock()
+// StartWithTimeout begins a new progress operation that fails automatically once the timeout passes.
+// The spinner shows the remaining time, and the returned context expires together with the operation,
+// with a cause wrapping ErrTimeout, so the caller's work can stop.
+// A timed out operation must still be completed with Finish or Fail, which then display nothing.
+func (p *ProgressDisplay) StartWithTimeout(message string, timeout time.Duration) (context.Context, error) {
+	operation := &ProgressOperation{Message: message, timeout: timeout}
+	p.start(operation)
 
-	if len(p.progressStack) == 0 {
-		// Updating an inexistent operation is not an error
-		return nil
-	}
+	ctx, cancel := context.WithDeadlineCause(
+		context.Background(),
+		operation.StartTime.Add(timeout),
+		newTimeoutError(timeout),
+	)
 
-	// Update the most recent progress operation
-	currentIndex := len(p.progressStack) - 1
-	p.progressStack[currentIndex].Message = message
+	p.stackMutex.Lock()
+	operation.cancelWork = cancel
+	p.stackMutex.Unlock()
 
-	return nil
+	go p.watchDeadline(ctx, operation)
+
+	return ctx, nil
 }
 
-// Finish completes the current progress operation successfully.
-func (p *ProgressDisplay) Finish(message string) error {
+// start pushes a new operation onto the progress stack, where the render loop picks it up.
+// The operation's hierarchy and timing fields are filled in here.
+func (p *ProgressDisplay) start(operation *ProgressOperation) {
 	p.stackMutex.Lock()
+	level := len(p.progressStack)
 
-	if len(p.progressStack) == 0 {
-		p.stackMutex.Unlock()
-		return nil
-	}
-
-	// Pop from progress stack
-	currentIndex := len(p.progressStack) - 1
-	operation := p.progressStack[currentIndex]
-	p.progressStack = p.progressStack[:currentIndex]
-	p.stackMutex.Unlock()
-
-	// Stop the spinner for this operation
-	if operation.CancelFunc != nil {
-		operation.CancelFunc()
+	if level > 0 {
+		operation.parent = p.progressStack[level-1]
 	}
 
-	operation.SetDone()
-	operation.Success = true
+	operation.StartTime = time.Now()
+	operation.CancelFunc = func() {}
+	operation.touch()
 
-	// Wait for spinner goroutine to complete cleanup before proceeding
-	p.spinnerWaitGroup.Wait()
-
-	// Ensure cursor is restored before displaying completion message
-	if restoreErr := p.restoreCursor(); restoreErr != nil {
-		return restoreErr
+	if operation.key == "" {
+		operation.key = operation.Message
 	}
+	operation.Level = level
+	p.progressStack = append(p.progressStack, operation)
+	p.stackMutex.Unlock()
 
-	// Decrement operation counter
-	p.operationInProgress.Add(-1)
+	// Increment operation counter
+	p.operationInProgress.Add(1)
 
-	// Display completion message
-	if displayErr := p.displayCompletion(operation, true, nil); displayErr != nil {
-		return displayErr
+	if p.accessible {
+		//nolint:errcheck // Announcing errors surface on the operation's completion
+		_ = p.announceStart(operation)
 	}
 
-	// Resume parent operation if exists
-	p.stackMutex.Lock()
-	p.resumeParentOperation()
-	p.stackMutex.Unlock()
+	//nolint:errcheck // Drawing errors surface on the operation's completion
+	_ = p.refresh()
 
-	return nil
+	p.emit(EventStart, operation, "")
 }
 
-// Fail completes the current progress operation with an error.
-func (p *ProgressDisplay) Fail(message string, err error) error {
+// Update modifies the message of the current progress operation.
+func (p *ProgressDisplay) Update(message string) error {
 	p.stackMutex.Lock()
 
 	if len(p.progressStack) == 0 {
 		p.stackMutex.Unlock()
+
+		// Updating an inexistent operation is not an error
 		return nil
 	}
 
-	// Pop from progress stack
-	currentIndex := len(p.progressStack) - 1
-	operation := p.progressStack[currentIndex]
-	p.progressStack = p.progressStack[:currentIndex]
+	// Update the most recent progress operation
+	operation := p.progressStack[len(p.progressStack)-1]
+	operation.Message = message
+	operation.touch()
 	p.stackMutex.Unlock()
 
-	// Stop the spinner for this operation
-	if operation.CancelFunc != nil {
-		operation.CancelFunc()
+	if p.accessible {
+		//nolint:errcheck // Announcing errors surface on the operation's completion
+		_ = p.announceUpdate(message)
 	}
 
-	operation.SetDone()
-	operation.Success = false
-	operation.Error = err
-
-	// Wait for spinner goroutine to complete cleanup before proceeding
-	p.spinnerWaitGroup.Wait()
-
-	// Ensure cursor is restored before displaying completion message
-	if restoreErr := p.restoreCursor(); restoreErr != nil {
-		return restoreErr
-	}
+	//nolint:errcheck // Drawing errors surface on the operation's completion
+	_ = p.refresh()
 
-	// Decrement operation counter
-	p.operationInProgress.Add(-1)
+	p.emit(EventUpdate, operation, "")
 
-	// Display failure message
-	if displayErr := p.displayCompletion(operation, false, err); displayErr != nil {
-		return displayErr
-	}
+	return nil
+}
 
-	// Resume parent operation if exists
-	p.stackMutex.Lock()
-	p.resumeParentOperation()
-	p.stackMutex.Unlock()
+// Finish completes the current progress operation successfully.
+func (p *ProgressDisplay) Finish(message string) error {
+	return p.completeOperation(true, nil)
+}
 
-	return nil
+// Fail completes the current progress operation with an error.
+func (p *ProgressDisplay) Fail(message string, err error) error {
+	return p.completeOperation(false, err)
 }
 
 // IsActive returns true if there are any active progress operations.
@@ -294,30 +396,38 @@ func (p *ProgressDisplay) IsActive() bool {
 }
 
 // Clear stops all progress operations without displaying completion messages.
+// Observers receive an EventCancel for every operation that didn't complete, innermost first.
 func (p *ProgressDisplay) Clear() error {
 	p.stackMutex.Lock()
-	// Stop all active spinners
+
+	var canceled []*ProgressOperation
+
+	// Release the contexts of timed operations
 	for _, operation := range p.progressStack {
-		if operation.CancelFunc != nil {
-			operation.CancelFunc()
+		if operation.cancelWork != nil {
+			operation.cancelWork()
 		}
 
-		operation.SetDone()
+		// Operations that timed out already reported their outcome
+		if operation.done.CompareAndSwap(0, 1) {
+			operation.duration = time.Since(operation.StartTime)
+			canceled = append(canceled, operation)
+		}
 	}
 
 	// Clear the stack and reset counter
 	p.progressStack = nil
-	p.activeSpinner = nil
 	p.stackMutex.Unlock()
 
-	// Wait for all spinner goroutines to complete
-	p.spinnerWaitGroup.Wait()
+	for _, operation := range slices.Backward(canceled) {
+		p.emit(EventCancel, operation, "")
+	}
 
 	p.operationInProgress.Store(0)
 	p.paused.Store(0)
 
-	// Restore cursor if it was hidden
-	return p.restoreCursor()
+	// Clear the live line and restore the cursor, the render loop stops on its next frame
+	return p.refresh()
 }
 
 // Pause temporarily stops all spinner operations for interactive commands.
@@ -325,24 +435,49 @@ func (p *ProgressDisplay) Pause() error {
 	p.pauseMutex.Lock()
 	defer p.pauseMutex.Unlock()
 
+	return p.pause()
+}
+
+// pauseIfRunning pauses the display unless it's paused already, and reports whether it paused it.
+func (p *ProgressDisplay) pauseIfRunning() (bool, error) {
+	p.pauseMutex.Lock()
+	defer p.pauseMutex.Unlock()
+
+	if p.IsPaused() {
+		return false, nil
+	}
+
+	return true, p.pause()
+}
+
+// pause stops drawing the live line and clears it.
+// Note: This method assumes the caller holds pauseMutex.
+func (p *ProgressDisplay) pause() error {
 	// Set paused state first
 	p.paused.Store(1)
+	p.emit(EventPause, p.currentOperation(), "")
 
-	// Cancel all active spinners
-	for _, operation := range p.progressStack {
-		if operation.CancelFunc != nil {
-			operation.CancelFunc()
-		}
-	}
+	// The render loop draws no frame once paused, and stops on its next frame
+	p.renderMutex.Lock()
+	defer p.renderMutex.Unlock()
 
-	// Wait for all spinner goroutines to finish
-	p.spinnerWaitGroup.Wait()
+	if p.embedded != nil {
+		p.embedded.signal()
+		return nil
+	}
 
 	// Now it's safe to clean up terminal state
 	if err := p.restoreCursor(); err != nil {
 		return err
 	}
 
+	p.frameDrawn = false
+
+	// Nothing was drawn in accessible mode
+	if p.accessible {
+		return nil
+	}
+
 	if file, ok := p.rawOutput.(*os.File); ok {
 		//nolint:errcheck // Best effort write during pause, errors not critical
 		file.WriteString("\r" + clearLine)
@@ -359,31 +494,15 @@ func (p *ProgressDisplay) Resume() error {
 	defer p.pauseMutex.Unlock()
 
 	p.paused.Store(0)
+	p.emit(EventResume, p.currentOperation(), "")
 
-	// Resume the most recent operation if there is one
-	p.stackMutex.Lock()
-
-	if len(p.progressStack) > 0 {
-		currentOperation := p.progressStack[len(p.progressStack)-1]
-		if !currentOperation.IsDone() {
-			// Create new context for resumed operation
-			ctx, cancel := context.WithCancel(context.Background())
-			currentOperation.CancelFunc = cancel
-
-			// Resume spinner for current operation
-			displayMessage := p.buildContextualMessage()
-			p.stackMutex.Unlock()
-			p.spinnerWaitGroup.Add(1)
-
-			go p.runSpinner(ctx, currentOperation, displayMessage)
-		} else {
-			p.stackMutex.Unlock()
-		}
-	} else {
-		p.stackMutex.Unlock()
+	// Time spent paused, e.g. waiting for user input, isn't a stall
+	if currentOperation := p.currentOperation(); currentOperation != nil {
+		currentOperation.touch()
 	}
 
-	return nil
+	// Resume drawing the most recent operation if there is one
+	return p.refresh()
 }
 
 // IsPaused returns whether the progress display is currently paused.
@@ -393,33 +512,79 @@ func (p *ProgressDisplay) IsPaused() bool {
 
 // StartPersistent begins a persistent progress operation that shows accomplishments.
 func (p *ProgressDisplay) StartPersistent(message string) error {
-	p.persistentMode = true
-	return p.Start(message)
+	p.start(&ProgressOperation{Message: message, Persistent: true})
+
+	return nil
 }
 
 // LogAccomplishment logs an accomplishment that stays visible.
+// The accomplishment is indented under the closest persistent operation it belongs to.
 func (p *ProgressDisplay) LogAccomplishment(message string) error {
-	checkmark := lipgloss.NewStyle().Foreground(lipgloss.Color("#2ecc71")).Render("✓")
-	_, err := fmt.Fprintf(p.output, "\r%s   %s %s\n", clearLine, checkmark, message)
+	p.emit(EventAccomplishment, p.currentOperation(), message)
 
-	return err
+	checkmark := lipgloss.NewStyle().Foreground(lipgloss.Color("#2ecc71")).Render(p.glyphs.success)
+
+	return p.writeLine(p.resultOutput, p.indentLogLine()+checkmark+" "+message)
+}
+
+// Log prints a dimmed informational line beneath the current operation.
+// Log lines are part of the progress narrative, so they go to the live output rather than the results.
+func (p *ProgressDisplay) Log(message string) error {
+	p.emit(EventLog, p.currentOperation(), message)
+
+	note := lipgloss.NewStyle().Faint(true).Render(message)
+
+	return p.writeLine(p.output, p.indentLogLine()+note)
+}
+
+// indentLogLine records a logged line as progress of the current operation and returns its indentation.
+// Lines are indented under the closest persistent operation they belong to.
+func (p *ProgressDisplay) indentLogLine() string {
+	p.stackMutex.RLock()
+	defer p.stackMutex.RUnlock()
+
+	if len(p.progressStack) > 0 {
+		p.progressStack[len(p.progressStack)-1].touch()
+	}
+
+	for i := len(p.progressStack) - 1; i >= 0; i-- {
+		if p.progressStack[i].Persistent {
+			return p.progressStack[i].accomplishmentIndent()
+		}
+	}
+
+	return accomplishmentIndentUnit
 }
 
 // FinishPersistent completes persistent progress with success.
 func (p *ProgressDisplay) FinishPersistent(message string) error {
-	p.persistentMode = false
 	return p.Finish(message)
 }
 
 // FailPersistent completes persistent progress with failure.
 func (p *ProgressDisplay) FailPersistent(message string, err error) error {
-	p.persistentMode = false
 	return p.Fail(message, err)
 }
 
-// Close ensures proper cleanup of terminal state.
+// Close ensures proper cleanup of terminal state, delivers pending events to observers,
+// and saves the display's history if it has one.
 func (p *ProgressDisplay) Close() error {
-	return p.Clear()
+	clearErr := p.Clear()
+
+	p.renderMutex.Lock()
+	if p.embedded != nil {
+		p.embedded.close()
+	}
+	p.renderMutex.Unlock()
+
+	// Observers of the last events may still be running
+	p.events.flush()
+
+	if p.history != nil {
+		return errors.Join(clearErr, p.history.Save())
+	}
+
+	return clearErr
 }
 
 // setupCleanup sets up signal handlers and cleanup mechanisms to ensure cursor is restored.
@@ -428,156 +593,238 @@ func (p *ProgressDisplay) setupCleanup() {
 	// The cursor restoration will happen when Clear() is called or through defer.
 }
 
-// resumeParentOperation resumes the spinner for the parent operation if one exists.
-// Note: This method assumes the caller holds a lock on stackMutex.
-func (p *ProgressDisplay) resumeParentOperation() {
+// completeOperation pops the current operation off the stack and displays its outcome.
+func (p *ProgressDisplay) completeOperation(success bool, err error) error {
+	p.stackMutex.Lock()
+
 	if len(p.progressStack) == 0 {
-		p.activeSpinner = nil
-		return
+		p.stackMutex.Unlock()
+		return nil
 	}
 
-	prevOperation := p.progressStack[len(p.progressStack)-1]
-	if !prevOperation.IsDone() {
-		p.activeSpinner = prevOperation
+	// Pop from progress stack
+	currentIndex := len(p.progressStack) - 1
+	operation := p.progressStack[currentIndex]
+	p.progressStack = p.progressStack[:currentIndex]
+	cancelWork := operation.cancelWork
+	p.stackMutex.Unlock()
 
-		// Create new context for resumed operation
-		ctx, cancel := context.WithCancel(context.Background())
-		prevOperation.CancelFunc = cancel
+	if cancelWork != nil {
+		cancelWork()
+	}
 
-		// Resume spinner for previous operation
-		displayMessage := p.buildContextualMessage()
+	var displayErr error
 
-		p.spinnerWaitGroup.Add(1)
+	// An operation that already timed out has displayed its outcome
+	if operation.done.CompareAndSwap(0, 1) {
+		displayErr = p.endOperation(operation, success, err)
+	}
 
-		go p.runSpinner(ctx, prevOperation, displayMessage)
+	// A completed child counts as progress of its parent, which the live line shows again
+	if operation.parent != nil {
+		operation.parent.touch()
 	}
+
+	return errors.Join(displayErr, p.refresh())
 }
 
-// displayCompletion shows the completion message for an operation.
-func (p *ProgressDisplay) displayCompletion(operation *ProgressOperation, success bool, err error) error {
-	duration := time.Since(operation.StartTime)
+// endOperation records the outcome of a completed operation and displays it.
+// The caller must have marked the operation as done, which stops the live line from drawing it.
+func (p *ProgressDisplay) endOperation(operation *ProgressOperation, success bool, err error) error {
+	// Renders and observers read the outcome concurrently, e.g. when the timeout watcher ends the operation
+	p.stackMutex.Lock()
+	operation.Success = success
+	operation.Error = err
+	operation.duration = time.Since(operation.StartTime)
+	operation.outcome = operationOutcome{
+		err:      err,
+		message:  operation.Message,
+		duration: operation.duration,
+		success:  success,
+	}
+	p.stackMutex.Unlock()
 
-	// In persistent mode, don't show individual completion messages
-	// unless it's the top-level operation
-	if p.persistentMode && operation.Level > 0 {
-		return nil
+	p.recordDuration(operation)
+
+	if success {
+		p.emit(EventFinish, operation, "")
+	} else {
+		p.emit(EventFail, operation, "")
+	}
+
+	// Decrement operation counter
+	p.operationInProgress.Add(-1)
+
+	// Display completion message
+	return p.displayCompletion(operation)
+}
+
+// displayCompletion shows the completion message for an operation.
+func (p *ProgressDisplay) displayCompletion(operation *ProgressOperation) error {
+	// Without a spinner showing them, every completion is announced
+	if p.accessible {
+		return p.announceCompletion(operation.outcome)
 	}
 
 	var displayMessage string
 
-	if success {
-		if duration > durationDisplayThreshold {
-			displayMessage = fmt.Sprintf("%s (took %v)", operation.Message, duration.Round(durationRoundPrecision))
-		} else {
-			displayMessage = operation.Message
+	if owner := operation.persistentAncestor(); owner != nil {
+		// Regular operations inside a persistent one don't show individual completion messages,
+		// while nested persistent operations report like an accomplishment of their owner
+		if !operation.Persistent {
+			return nil
+		}
+
+		indent := owner.accomplishmentIndent()
+
+		displayMessage = indent + p.completionLine(operation.outcome)
+		if operation.outcome.err != nil {
+			displayMessage += "\n" + p.formatError(operation.outcome.err, indent+errorIndent)
+		}
+	} else if p.treeOutput {
+		tree := p.settleTree(operation)
+		if tree == nil {
+			return nil
 		}
 
-		// Print success message without indentation for minimal output
-		checkmark := lipgloss.NewStyle().Foreground(lipgloss.Color("#2ecc71")).Render("✓")
-		fmt.Fprintf(p.output, "\r%s%s %s\n", clearLine, checkmark, displayMessage)
+		operation = tree
+		displayMessage = p.renderTree(tree)
 	} else {
-		if duration > durationDisplayThreshold {
-			displayMessage = fmt.Sprintf("%s (failed after %v)", operation.Message, duration.Round(durationRoundPrecision))
-		} else {
-			displayMessage = operation.Message
+		// Print message without indentation for minimal output
+		displayMessage = p.completionLine(operation.outcome)
+		if operation.outcome.err != nil {
+			displayMessage += "\n" + p.formatError(operation.outcome.err, errorIndent)
 		}
+	}
 
-		// Print failure message without indentation for minimal output
-		cross := lipgloss.NewStyle().Foreground(lipgloss.Color("#e74c3c")).Render("✗")
-		errorMsg := fmt.Sprintf("\r%s%s %s", clearLine, cross, displayMessage)
+	output := p.resultOutput
+	if !operation.outcome.success {
+		output = p.errorOutput
+	}
 
-		if err != nil {
-			errorMsg += fmt.Sprintf("\n  Error: %v", err)
-		}
+	return p.writeLine(output, displayMessage)
+}
+
+// writeLine writes a permanent line to the given stream.
+// The live line is cleared first, so a spinner frame never ends up mixed into the line.
+func (p *ProgressDisplay) writeLine(output io.Writer, line string) error {
+	p.renderMutex.Lock()
+	defer p.renderMutex.Unlock()
+
+	if p.embedded != nil {
+		p.embedded.print(line)
+		return nil
+	}
+
+	// The next frame is drawn below the line
+	frameDrawn := p.frameDrawn
+	p.frameDrawn = false
+
+	// Permanent lines are announcements too, delaying the next status repeat
+	p.lastAnnouncement = time.Now()
 
-		// Write to stderr for errors, but use the configured output writer
-		if p.rawOutput == os.Stdout {
-			_, err := fmt.Fprintf(os.Stderr, "%s\n", errorMsg)
-			if err != nil {
-				return err
-			}
-		} else {
-			_, err := fmt.Fprintf(p.output, "%s\n", errorMsg)
-			if err != nil {
-				return err
-			}
+	// Accessible output is never overwritten, so it needs no control sequences
+	if output == p.output && !p.accessible {
+		_, err := fmt.Fprintf(output, "\r%s%s\n", clearLine, line)
+		return err
+	}
+
+	// Clear the live line on its own stream, keeping the other stream free of control sequences
+	if frameDrawn {
+		_, _ = fmt.Fprint(p.output, "\r"+clearLine)
+	}
+
+	_, err := fmt.Fprintln(output, line)
+
+	return err
+}
+
+// completionLine formats the outcome of an operation, including its duration when meaningful.
+func (p *ProgressDisplay) completionLine(outcome operationOutcome) string {
+	if outcome.success {
+		message := outcome.message
+		if outcome.duration > durationDisplayThreshold {
+			message = fmt.Sprintf("%s (took %v)", message, outcome.duration.Round(durationRoundPrecision))
 		}
+
+		checkmark := lipgloss.NewStyle().Foreground(lipgloss.Color("#2ecc71")).Render(p.glyphs.success)
+
+		return checkmark + " " + message
 	}
 
-	return nil
+	message := outcome.message
+	if outcome.duration > durationDisplayThreshold {
+		message = fmt.Sprintf("%s (failed after %v)", message, outcome.duration.Round(durationRoundPrecision))
+	}
+
+	cross := lipgloss.NewStyle().Foreground(lipgloss.Color("#e74c3c")).Render(p.glyphs.failure)
+
+	return cross + " " + message
 }
 
-// runSpinner runs a spinner for the given operation in the background.
-func (p *ProgressDisplay) runSpinner(ctx context.Context, operation *ProgressOperation, displayMessage string) {
-	// Signal completion when function exits
-	defer p.spinnerWaitGroup.Done()
+// spinnerTitle decorates the contextual message of an operation with its live status,
+// fitting it into the given display width, unless it's zero.
+func (p *ProgressDisplay) spinnerTitle(operation *ProgressOperation, width int) string {
+	var parts []string
 
-	// Don't start spinner if paused
-	if p.IsPaused() {
-		return
-	}
-
-	// Mark cursor as hidden when spinner starts
-	p.cursorHidden.Store(1)
-
-	// Create spinner with huh
-	s := spinner.New().
-		Title(displayMessage).
-		Type(spinner.Dots).
-		Output(p.output).
-		Accessible(false).
-		Context(ctx)
-
-	// Run spinner with a simple action that waits for completion
-	s.ActionWithErr(func(spinnerCtx context.Context) error {
-		ticker := time.NewTicker(spinnerTickInterval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-spinnerCtx.Done():
-				return spinnerCtx.Err()
-			case <-ticker.C:
-				// Stop spinner if paused
-				if p.IsPaused() {
-					return nil
-				}
-
-				if operation == nil {
-					return errors.New("no operation in progress")
-				}
-
-				if operation.IsDone() {
-					return nil
-				}
-			}
+	if p.elapsedAncestors {
+		parts = p.timedContextParts(operation)
+	} else {
+		parts = p.contextParts(operation)
+		if label := p.elapsedLabel(operation); label != "" {
+			parts[len(parts)-1] += " " + label
 		}
-	})
+	}
 
-	// Run the spinner
-	//nolint:errcheck // Spinner errors are handled by context cancellation
-	_ = s.Run()
+	return p.fitTitle(parts, p.liveStatus(operation), width)
 }
 
-// buildContextualMessage creates a hierarchical message showing the full context
-// Note: This method assumes the caller holds a lock on stackMutex.
-func (p *ProgressDisplay) buildContextualMessage() string {
-	if len(p.progressStack) == 0 {
-		return ""
+// liveStatus returns the live status shown after the message of an operation:
+// its estimated remaining time, its timeout countdown and its stall warning, each preceded by a space.
+func (p *ProgressDisplay) liveStatus(operation *ProgressOperation) string {
+	var status string
+
+	if estimate := p.estimateLabel(operation); estimate != "" {
+		status += " " + estimate
 	}
 
-	// Build context from all operations in the stack
-	parts := make([]string, 0, len(p.progressStack))
+	if operation.timeout > 0 {
+		remaining := max(time.Until(operation.StartTime.Add(operation.timeout)), 0)
+		status += fmt.Sprintf(" (%v left)", roundUpToSecond(remaining))
+	}
 
-	for _, op := range p.progressStack {
-		parts = append(parts, op.Message)
+	if warning := p.stallWarning(operation); warning != "" {
+		status += " " + warning
 	}
 
+	return status
+}
+
+// contextualMessage creates a hierarchical message showing the full context of an operation,
+// made of its current message and those of its ancestors.
+func (p *ProgressDisplay) contextualMessage(operation *ProgressOperation) string {
 	// Join with separator to show hierarchy
-	return strings.Join(parts, ": ")
+	return strings.Join(p.contextParts(operation), contextSeparator)
+}
+
+// contextParts returns the messages of an operation and its ancestors, outermost first.
+func (p *ProgressDisplay) contextParts(operation *ProgressOperation) []string {
+	p.stackMutex.RLock()
+	defer p.stackMutex.RUnlock()
+
+	var parts []string
+
+	for current := operation; current != nil; current = current.parent {
+		parts = append(parts, current.Message)
+	}
+
+	slices.Reverse(parts)
+
+	return parts
 }
 
 // restoreCursor ensures the terminal cursor is visible.
+// Note: This method assumes the caller holds renderMutex, so no frame hides the cursor again meanwhile.
 func (p *ProgressDisplay) restoreCursor() error {
 	// Only restore cursor if it was actually hidden
 	// If CompareAndSwap fails, it means cursor wasn't hidden, which is fine
@@ -591,7 +838,7 @@ func (p *ProgressDisplay) restoreCursor() error {
 			return err
 		}
 	} else {
-		_, err := fmt.Fprint(p.rawOutput, showCursor)
+		_, err := fmt.Fprint(p.output, showCursor)
 		if err != nil {
 			return err
 		}
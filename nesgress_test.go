@@ -93,6 +93,32 @@ func Test_ProgressMessage_UpdatedAfterStart_ShowsNewMessage(t *testing.T) {
 	require.Contains(t, output, "Updated message")
 }
 
+func Test_Update_WhileSpinnerRuns_RedrawsLiveTitle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Downloading")
+	_ = display.Start("Fetching packages")
+	_ = display.Update("Fetching packages 3/10")
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(display.GetOutputSafely(), "Downloading: Fetching packages 3/10")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_ = display.Finish("Fetching packages")
+	_ = display.Update("Downloading 2/2")
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(display.GetOutputSafely(), "Downloading 2/2")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_ = display.Finish("Downloading")
+}
+
 func Test_ProgressOperation_WhenFailed_ShowsErrorMessage(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -497,6 +523,97 @@ func Test_PersistentProgress_WithAccomplishments_ShowsInRealTime(t *testing.T) {
 	require.Contains(t, output, "Processing items")
 }
 
+func Test_NestedPersistentProgress_WithAccomplishments_IndentsUnderOwner(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.StartPersistent("Outer")
+	_ = display.LogAccomplishment("Outer step")
+	_ = display.StartPersistent("Inner")
+	_ = display.LogAccomplishment("Inner step")
+	_ = display.FinishPersistent("Inner done")
+	_ = display.FinishPersistent("Outer done")
+
+	lines := completionLines(display.GetOutputSafely())
+	require.Equal(t, []string{
+		"   ✓ Outer step",
+		"      ✓ Inner step",
+		"   ✓ Inner",
+		"✓ Outer",
+	}, lines)
+}
+
+func Test_NestedPersistentProgress_WhenInnerFinishes_KeepsOuterSuppression(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.StartPersistent("Outer")
+	_ = display.StartPersistent("Inner")
+	_ = display.FinishPersistent("Inner done")
+
+	_ = display.Start("Regular child")
+	_ = display.Finish("Regular child")
+
+	_ = display.FinishPersistent("Outer done")
+
+	output := display.GetOutputSafely()
+	require.NotContains(t, output, "✓ Regular child")
+	require.Contains(t, output, "✓ Outer")
+}
+
+func Test_PersistentProgress_InsideRegularOperation_ShowsOwnCompletion(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Regular")
+	_ = display.StartPersistent("Persistent")
+	_ = display.LogAccomplishment("Step")
+	_ = display.FinishPersistent("Persistent done")
+	_ = display.Finish("Regular")
+
+	lines := completionLines(display.GetOutputSafely())
+	require.Equal(t, []string{"   ✓ Step", "✓ Persistent", "✓ Regular"}, lines)
+}
+
+func Test_PersistentProgress_FromConcurrentGoroutines_IsRaceFree(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	done := make(chan bool, 2)
+
+	go func() {
+		_ = display.StartPersistent("Concurrent persistent")
+		_ = display.LogAccomplishment("First step")
+		time.Sleep(50 * time.Millisecond)
+		_ = display.FinishPersistent("Concurrent persistent")
+
+		done <- true
+	}()
+
+	go func() {
+		time.Sleep(25 * time.Millisecond)
+		_ = display.LogAccomplishment("Second step")
+		_ = display.Start("Concurrent regular")
+		time.Sleep(50 * time.Millisecond)
+		_ = display.Finish("Concurrent regular")
+
+		done <- true
+	}()
+
+	<-done
+	<-done
+
+	require.False(t, display.IsActive())
+
+	output := buf.String()
+	require.Contains(t, output, "First step")
+	require.Contains(t, output, "Second step")
+}
+
 func Test_NoopProgressDisplay_PersistentMethods_DoNothing(t *testing.T) {
 	display := nesgress.NewNoopProgressDisplay()
 
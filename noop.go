@@ -1,9 +1,28 @@
 package nesgress
 
+import (
+	"context"
+	"sync"
+	"time"
+)
+
 // NoopProgressDisplay is a progress display that does nothing.
-type NoopProgressDisplay struct{}
+// It only keeps track of the contexts handed out by StartWithTimeout, to release them on completion.
+type NoopProgressDisplay struct {
+	operations releaseStack
+}
+
+// releaseStack tracks the functions releasing the contexts of running operations, innermost last.
+type releaseStack struct {
+	releases []context.CancelFunc // per running operation, nil for operations without a context to release
+	mutex    sync.Mutex
+}
 
-var _ ProgressReporter = (*NoopProgressDisplay)(nil)
+var (
+	_ ProgressReporter = (*NoopProgressDisplay)(nil)
+	_ TimeoutStarter   = (*NoopProgressDisplay)(nil)
+	_ Logger           = (*NoopProgressDisplay)(nil)
+)
 
 // NewNoopProgressDisplay creates a progress display that does nothing.
 func NewNoopProgressDisplay() *NoopProgressDisplay {
@@ -12,29 +31,87 @@ func NewNoopProgressDisplay() *NoopProgressDisplay {
 
 // Start does nothing.
 func (n *NoopProgressDisplay) Start(message string) error {
+	n.operations.push(nil)
+
 	return nil
 }
 
+// StartWithTimeout displays nothing, but still returns a context that expires once the timeout passes,
+// and is released once the operation completes.
+func (n *NoopProgressDisplay) StartWithTimeout(message string, timeout time.Duration) (context.Context, error) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), timeout, newTimeoutError(timeout))
+	n.operations.push(cancel)
+
+	return ctx, nil
+}
+
+// push tracks a new operation, along with the function releasing its context, if it has one.
+func (s *releaseStack) push(release context.CancelFunc) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.releases = append(s.releases, release)
+}
+
+// pop stops tracking the current operation and releases its context, if it has one.
+func (s *releaseStack) pop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.releases) == 0 {
+		return
+	}
+
+	release := s.releases[len(s.releases)-1]
+	s.releases = s.releases[:len(s.releases)-1]
+
+	if release != nil {
+		release()
+	}
+}
+
+// releaseAll stops tracking all operations and releases their contexts.
+func (s *releaseStack) releaseAll() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, release := range s.releases {
+		if release != nil {
+			release()
+		}
+	}
+
+	s.releases = nil
+}
+
 // Update does nothing.
 func (n *NoopProgressDisplay) Update(message string) error {
 	return nil
 }
 
-// Finish does nothing.
+// Finish displays nothing, but releases the context of an operation started with StartWithTimeout.
 func (n *NoopProgressDisplay) Finish(message string) error {
+	n.operations.pop()
+
 	return nil
 }
 
-// Fail does nothing.
+// Fail displays nothing, but releases the context of an operation started with StartWithTimeout.
 func (n *NoopProgressDisplay) Fail(message string, err error) error {
+	n.operations.pop()
+
 	return nil
 }
 
 // IsActive always returns false.
 func (n *NoopProgressDisplay) IsActive() bool { return false }
 
-// Clear does nothing.
-func (n *NoopProgressDisplay) Clear() error { return nil }
+// Clear displays nothing, but releases the contexts of operations started with StartWithTimeout.
+func (n *NoopProgressDisplay) Clear() error {
+	n.operations.releaseAll()
+
+	return nil
+}
 
 // Pause does nothing.
 func (n *NoopProgressDisplay) Pause() error {
@@ -51,6 +128,8 @@ func (n *NoopProgressDisplay) IsPaused() bool { return false }
 
 // StartPersistent does nothing.
 func (n *NoopProgressDisplay) StartPersistent(message string) error {
+	n.operations.push(nil)
+
 	return nil
 }
 
@@ -59,15 +138,22 @@ func (n *NoopProgressDisplay) LogAccomplishment(message string) error {
 	return nil
 }
 
+// Log does nothing.
+func (n *NoopProgressDisplay) Log(message string) error {
+	return nil
+}
+
 // FinishPersistent does nothing.
 func (n *NoopProgressDisplay) FinishPersistent(message string) error {
-	return nil
+	return n.Finish(message)
 }
 
 // FailPersistent does nothing.
 func (n *NoopProgressDisplay) FailPersistent(message string, err error) error {
-	return nil
+	return n.Fail(message, err)
 }
 
-// Close does nothing.
-func (n *NoopProgressDisplay) Close() error { return nil }
+// Close releases the contexts of running operations, like Clear.
+func (n *NoopProgressDisplay) Close() error {
+	return n.Clear()
+}
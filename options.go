@@ -0,0 +1,165 @@
+package nesgress
+
+import (
+	"io"
+	"time"
+)
+
+// Option configures optional behavior of a ProgressDisplay.
+type Option func(*ProgressDisplay)
+
+// WithTreeOutput renders completed operations as an indented tree instead of a flat list.
+//
+// Completions of nested operations are held back until their top-level operation ends,
+// at which point the whole subtree is printed with the parent above its children:
+//
+//	✓ Setting up environment (took 1.5s)
+//	├─ ✓ Downloading dependencies (took 1s)
+//	└─ ✓ Compiling (took 500ms)
+func WithTreeOutput() Option {
+	return func(p *ProgressDisplay) {
+		p.treeOutput = true
+	}
+}
+
+// WithErrorDetails renders the %+v form of failure errors beneath the error list
+// when it carries more than the error message, such as stack traces.
+func WithErrorDetails() Option {
+	return func(p *ProgressDisplay) {
+		p.errorDetails = true
+	}
+}
+
+// WithLiveOutput sends the ephemeral live UI, such as spinners, to the given writer
+// instead of the display's output. A nil writer keeps the display's output.
+func WithLiveOutput(w io.Writer) Option {
+	return func(p *ProgressDisplay) {
+		if w != nil {
+			p.rawOutput = w
+		}
+	}
+}
+
+// WithResultOutput sends permanent success lines, such as completions and accomplishments,
+// to the given writer instead of the display's output.
+//
+// Lines written to a stream other than the live one carry no terminal control sequences,
+// which keeps a piped stdout clean while progress goes to stderr:
+//
+//	display := nesgress.NewProgressDisplay(os.Stderr, nesgress.WithResultOutput(os.Stdout))
+func WithResultOutput(w io.Writer) Option {
+	return func(p *ProgressDisplay) {
+		p.resultOutput = w
+	}
+}
+
+// WithErrorOutput sends permanent failure lines to the given writer.
+// By default failures go to stderr when the display's output is stdout, and to the output otherwise.
+func WithErrorOutput(w io.Writer) Option {
+	return func(p *ProgressDisplay) {
+		p.errorOutput = w
+	}
+}
+
+// WithStallThreshold marks operations as stalled once they make no progress for the given duration.
+// Updates, logged accomplishments and completed child operations count as progress.
+// Stalled operations show how long they have been idle next to their spinner.
+func WithStallThreshold(threshold time.Duration) Option {
+	return func(p *ProgressDisplay) {
+		p.stallThreshold = threshold
+	}
+}
+
+// WithStallHandler sets a handler called once whenever an operation stalls, e.g. to dump diagnostics.
+// It runs on its own goroutine so it can't hold up the display.
+func WithStallHandler(handler StallHandler) Option {
+	return func(p *ProgressDisplay) {
+		p.stallHandler = handler
+	}
+}
+
+// WithStartDelay draws the spinner of an operation only once it has been running for the given grace period,
+// e.g. 150ms. Operations completing sooner print just their completion line, without any spinner frames,
+// which avoids flicker in loops of fast operations. Meanwhile, the spinner keeps showing the enclosing operation.
+func WithStartDelay(delay time.Duration) Option {
+	return func(p *ProgressDisplay) {
+		p.startDelay = delay
+	}
+}
+
+// WithElapsedTime shows a running timer next to the spinner title once an operation
+// has been running for at least the given threshold.
+func WithElapsedTime(threshold time.Duration) Option {
+	return func(p *ProgressDisplay) {
+		p.showElapsed = true
+		p.elapsedThreshold = threshold
+	}
+}
+
+// WithElapsedFormat sets how the running timer enabled by WithElapsedTime is formatted.
+// The default renders durations like "(1m12s)".
+func WithElapsedFormat(format ElapsedFormatter) Option {
+	return func(p *ProgressDisplay) {
+		p.elapsedFormat = format
+	}
+}
+
+// WithAncestorElapsedTime shows the running timer of every operation in the spinner's context,
+// e.g. "Deploying (2m5s): Uploading (12s)". It implies WithElapsedTime when not set explicitly.
+func WithAncestorElapsedTime() Option {
+	return func(p *ProgressDisplay) {
+		p.showElapsed = true
+		p.elapsedAncestors = true
+	}
+}
+
+// WithHistory records the duration of successful operations in the given history,
+// and shows an estimate of the remaining time next to operations that ran before.
+// The history is saved when the display is closed.
+//
+//	history, err := nesgress.OpenHistory("mytool")
+//	display := nesgress.NewProgressDisplay(os.Stdout, nesgress.WithHistory(history))
+func WithHistory(history *History) Option {
+	return func(p *ProgressDisplay) {
+		p.history = history
+	}
+}
+
+// WithPromptInput reads the replies of the display's prompts, such as Confirm, from the given reader
+// instead of stdin, e.g. in tests.
+func WithPromptInput(input io.Reader) Option {
+	return func(p *ProgressDisplay) {
+		p.promptInput = input
+	}
+}
+
+// WithWidth clips the live line to the given number of columns, instead of the width of the terminal.
+// Lines too wide leave out the ancestors between the outermost one and the current operation first,
+// e.g. "Deploying: …: Compiling x.go", and are cut off at the end if that's not enough.
+func WithWidth(columns int) Option {
+	return func(p *ProgressDisplay) {
+		p.width.Store(int64(columns))
+	}
+}
+
+// WithASCII sets whether ASCII glyphs, such as "[ok]", "[FAIL]" and a "|/-\" spinner,
+// are drawn instead of the Unicode ones. By default they are drawn on terminals unlikely to render Unicode, judging by TERM and the locale,
+// e.g. the Linux console, serial consoles and non-UTF-8 locales.
+func WithASCII(ascii bool) Option {
+	return func(p *ProgressDisplay) {
+		p.glyphs = &unicodeGlyphs
+		if ascii {
+			p.glyphs = &asciiGlyphs
+		}
+	}
+}
+
+// WithAccessible announces progress in complete sentences for screen readers, instead of drawing it.
+// There's no animation and no line is overwritten: operations are announced when they start and complete,
+// with their outcome spelled out, and long operations repeat what they are doing at most every 10 seconds.
+// It's also enabled by setting the ACCESSIBLE environment variable to anything but a false value such as "0".
+func WithAccessible() Option {
+	return func(p *ProgressDisplay) {
+		p.accessible = true
+	}
+}
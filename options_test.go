@@ -0,0 +1,77 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_StreamRouting_WithSeparateWriters_SendsEachKindToItsStream(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var live, results, failures bytes.Buffer
+	display := nesgress.NewProgressDisplay(&live,
+		nesgress.WithResultOutput(&results),
+		nesgress.WithErrorOutput(&failures),
+	)
+
+	_ = display.Start("Succeeding")
+	time.Sleep(50 * time.Millisecond)
+	_ = display.LogAccomplishment("Step done")
+	_ = display.Finish("Succeeding")
+
+	_ = display.Start("Failing")
+	_ = display.Fail("Failing", errors.New("boom"))
+
+	require.Equal(t, "   ✓ Step done\n✓ Succeeding\n", results.String())
+	require.Equal(t, "✗ Failing\n  Error: boom\n", failures.String())
+
+	liveOutput := display.GetOutputSafely()
+	require.Contains(t, liveOutput, "Succeeding")
+	require.NotContains(t, liveOutput, "✓")
+	require.NotContains(t, liveOutput, "✗")
+}
+
+func Test_StreamRouting_WithLiveOutputOption_MovesSpinnersOffTheOutput(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var live, output bytes.Buffer
+	display := nesgress.NewProgressDisplay(&output, nesgress.WithLiveOutput(&live))
+
+	_ = display.Start("Working")
+	time.Sleep(50 * time.Millisecond)
+	_ = display.Finish("Working")
+
+	require.Equal(t, "✓ Working\n", output.String())
+	require.Contains(t, live.String(), "Working")
+}
+
+func Test_StreamRouting_ByDefault_WritesFailuresToTheOutput(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Failing")
+	_ = display.Fail("Failing", errors.New("boom"))
+
+	require.Contains(t, display.GetOutputSafely(), "✗ Failing")
+}
+
+func Test_StreamRouting_WithNilLiveOutput_KeepsTheOutput(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithLiveOutput(nil))
+
+	require.NoError(t, display.Start("Working"))
+	require.NoError(t, display.Finish("Working"))
+	require.NoError(t, display.Close())
+
+	require.Contains(t, display.GetOutputSafely(), "✓ Working")
+}
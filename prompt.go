@@ -0,0 +1,307 @@
+package nesgress
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+)
+
+// maskedAnswer is recorded in place of masked answers, the same for all of them so their length doesn't leak.
+const maskedAnswer = "********"
+
+// Styles of prompts.
+var (
+	questionStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#3498db"))
+	answerStyle   = lipgloss.NewStyle().Faint(true)
+	rejectStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#e74c3c"))
+)
+
+// InputOptions configures a prompt of Input.
+type InputOptions struct {
+	Validate func(string) error // rejects answers with an error, the prompt repeats until one is accepted
+	Default  string             // answer used when the reply is empty
+	Masked   bool               // hides the answer while typing and in the output, e.g. for passwords
+}
+
+// promptQuestion is a question asked by the display's built-in prompts.
+type promptQuestion struct {
+	// answer turns a reply into the text recorded in the output, or rejects it with an error before asking again
+	answer func(reply string) (string, error)
+	title  string   // recorded along with the answer
+	hint   string   // shown after the title while asking, e.g. the default answer
+	lines  []string // shown above the prompt, e.g. choices
+	masked bool
+}
+
+// Prompt pauses the display, runs the given prompt with the terminal to itself, and resumes the display.
+// The display resumes even if the prompt fails or panics, and panics carry on once it did.
+// When the display is paused already, the prompt runs without resuming it afterwards.
+// Concurrent prompts run one after the other, so prompts must not run other prompts of the same display.
+func (p *ProgressDisplay) Prompt(prompt func() error) error {
+	p.promptMutex.Lock()
+	defer p.promptMutex.Unlock()
+
+	return p.runPrompt(prompt)
+}
+
+// runPrompt runs a prompt with the display paused, resuming it afterwards if it was running.
+// Note: This method assumes the caller holds promptMutex.
+func (p *ProgressDisplay) runPrompt(prompt func() error) (err error) {
+	// Only the prompt that paused the display resumes it
+	paused, err := p.pauseIfRunning()
+	if err != nil {
+		return errors.Join(err, p.Resume())
+	}
+
+	if paused {
+		defer func() {
+			err = errors.Join(err, p.Resume())
+		}()
+	}
+
+	return prompt()
+}
+
+// PromptForm runs a huh form like Prompt runs a prompt.
+// In accessible mode, the form runs in huh's accessible mode as well.
+func (p *ProgressDisplay) PromptForm(form *huh.Form) error {
+	if p.accessible {
+		form = form.WithAccessible(true)
+	}
+
+	return p.Prompt(form.Run)
+}
+
+// Confirm asks a yes/no question beneath the current operation, defaulting to no.
+// Replies are read from the display's prompt input, and the answer is recorded in the output.
+func (p *ProgressDisplay) Confirm(question string) (bool, error) {
+	var confirmed bool
+
+	err := p.ask(promptQuestion{
+		title: question,
+		hint:  "[y/N]",
+		answer: func(reply string) (string, error) {
+			switch strings.ToLower(reply) {
+			case "y", "yes":
+				confirmed = true
+				return "yes", nil
+			case "", "n", "no":
+				confirmed = false
+				return "no", nil
+			default:
+				return "", errors.New("answer yes or no")
+			}
+		},
+	})
+
+	return confirmed, err
+}
+
+// Input asks for a line of text beneath the current operation.
+// Replies are read from the display's prompt input, and the answer is recorded in the output,
+// unless it's masked.
+func (p *ProgressDisplay) Input(label string, opts InputOptions) (string, error) {
+	var value string
+
+	var hint string
+	if opts.Default != "" && !opts.Masked {
+		hint = "(" + opts.Default + ")"
+	}
+
+	err := p.ask(promptQuestion{
+		title:  label,
+		hint:   hint,
+		masked: opts.Masked,
+		answer: func(reply string) (string, error) {
+			if reply == "" {
+				reply = opts.Default
+			}
+
+			if opts.Validate != nil {
+				if err := opts.Validate(reply); err != nil {
+					return "", err
+				}
+			}
+
+			value = reply
+
+			if opts.Masked {
+				return maskedAnswer, nil
+			}
+
+			return reply, nil
+		},
+	})
+
+	return value, err
+}
+
+// Select asks to pick one of the given choices, listed beneath the current operation.
+// A choice is picked by its number or its text. Replies are read from the display's prompt input,
+// and the picked choice is recorded in the output.
+func (p *ProgressDisplay) Select(label string, choices []string) (string, error) {
+	if len(choices) == 0 {
+		return "", errors.New("nothing to select from")
+	}
+
+	lines := make([]string, 0, len(choices))
+	for i, choice := range choices {
+		lines = append(lines, fmt.Sprintf("  %d) %s", i+1, choice))
+	}
+
+	var selected string
+
+	err := p.ask(promptQuestion{
+		lines: lines,
+		title: label,
+		hint:  fmt.Sprintf("[1-%d]", len(choices)),
+		answer: func(reply string) (string, error) {
+			if number, err := strconv.Atoi(reply); err == nil && number >= 1 && number <= len(choices) {
+				selected = choices[number-1]
+				return selected, nil
+			}
+
+			for _, choice := range choices {
+				if reply != "" && strings.EqualFold(reply, choice) {
+					selected = choice
+					return selected, nil
+				}
+			}
+
+			return "", fmt.Errorf("choose a number from 1 to %d", len(choices))
+		},
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return selected, nil
+}
+
+// ask pauses the display and asks the question until its answer is accepted.
+// On a terminal the prompt is then replaced by a line recording the question and its answer,
+// elsewhere and in accessible mode the line follows the prompt.
+// Questions asked concurrently wait for each other, as they read the same input.
+func (p *ProgressDisplay) ask(q promptQuestion) error {
+	p.promptMutex.Lock()
+	defer p.promptMutex.Unlock()
+
+	p.renderMutex.Lock()
+	embedded := p.embedded != nil
+	p.renderMutex.Unlock()
+
+	if embedded {
+		return errors.New("prompts can't read the terminal of a Bubble Tea program")
+	}
+
+	return p.runPrompt(func() error {
+		// Nothing watches the terminal's width while paused, and prompts erase themselves by it
+		p.measureWidth()
+
+		indent := p.indentLogLine()
+		drawn := 0 // terminal rows of the prompt, to be replaced by the answer
+
+		for _, line := range q.lines {
+			if _, err := fmt.Fprintln(p.output, indent+line); err != nil {
+				return err
+			}
+
+			drawn += p.terminalRows(indent + line)
+		}
+
+		prompt := indent + questionStyle.Render("?") + " " + q.title
+		if q.hint != "" {
+			prompt += " " + q.hint
+		}
+
+		for {
+			if _, err := fmt.Fprint(p.output, prompt+" "); err != nil {
+				return err
+			}
+
+			reply, err := p.readReply(q.masked)
+			if err != nil {
+				return err
+			}
+
+			echoed := reply
+			if q.masked {
+				echoed = ""
+			}
+
+			drawn += p.terminalRows(prompt + " " + echoed)
+
+			record, err := q.answer(reply)
+			if err == nil {
+				if isTerminal(p.rawOutput) && !p.accessible {
+					_, _ = fmt.Fprintf(p.output, "\033[%dA\r\033[J", drawn)
+				}
+
+				return p.writeLine(p.resultOutput, indent+questionStyle.Render("?")+" "+q.title+" "+answerStyle.Render(record))
+			}
+
+			rejection := indent + "  " + rejectStyle.Render(err.Error())
+			if _, err := fmt.Fprintln(p.output, rejection); err != nil {
+				return err
+			}
+
+			drawn += p.terminalRows(rejection)
+		}
+	})
+}
+
+// readReply reads a line from the prompt input, leaving the cursor at the start of the next line.
+// Masked replies are read without echoing them when the input is a terminal.
+func (p *ProgressDisplay) readReply(masked bool) (string, error) {
+	if file, ok := p.promptInput.(*os.File); ok && masked && isTerminal(file) {
+		reply, err := term.ReadPassword(file.Fd())
+
+		// Not even the line break is echoed
+		_, _ = fmt.Fprintln(p.output)
+
+		return string(reply), err
+	}
+
+	reply, err := p.readLine()
+	if err != nil {
+		return "", err
+	}
+
+	// Nothing echoes replies that aren't typed into a terminal, so the prompt shows them itself
+	if !isTerminal(p.promptInput) {
+		echo := reply
+		if masked {
+			echo = ""
+		}
+
+		_, _ = fmt.Fprintln(p.output, echo)
+	}
+
+	return reply, nil
+}
+
+// readLine reads a line from the prompt input without its line break.
+// The last line of the input may end without one.
+func (p *ProgressDisplay) readLine() (string, error) {
+	line, err := p.promptReader.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", fmt.Errorf("read reply: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// isTerminal reports whether the given stream is a terminal.
+func isTerminal(stream any) bool {
+	file, ok := stream.(*os.File)
+
+	return ok && term.IsTerminal(file.Fd())
+}
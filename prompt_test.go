@@ -0,0 +1,327 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_Prompt_WithActiveOperation_PausesWhilePrompting(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Installing")
+
+	err := display.Prompt(func() error {
+		require.True(t, display.IsPaused())
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.False(t, display.IsPaused())
+	require.True(t, display.IsActive())
+	require.NoError(t, display.Close())
+}
+
+func Test_Prompt_WhenPromptFails_ResumesAndReturnsError(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+	errAborted := errors.New("aborted")
+
+	err := display.Prompt(func() error {
+		return errAborted
+	})
+
+	require.ErrorIs(t, err, errAborted)
+	require.False(t, display.IsPaused())
+}
+
+func Test_Prompt_WhenPromptPanics_ResumesBeforePanicking(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	require.PanicsWithValue(t, "broken prompt", func() {
+		_ = display.Prompt(func() error {
+			panic("broken prompt")
+		})
+	})
+
+	require.False(t, display.IsPaused())
+}
+
+func Test_Prompt_WhenAlreadyPaused_StaysPaused(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	require.NoError(t, display.Pause())
+	require.NoError(t, display.Prompt(func() error { return nil }))
+	require.True(t, display.IsPaused())
+}
+
+func Test_Prompt_WithConcurrentPrompts_RunsThemOneAtATimeWhilePaused(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Installing")
+
+	var (
+		running  atomic.Int32
+		overlaps atomic.Int32
+		resumed  atomic.Int32
+		wg       sync.WaitGroup
+	)
+
+	for range 3 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_ = display.Prompt(func() error {
+				if running.Add(1) > 1 {
+					overlaps.Add(1)
+				}
+
+				time.Sleep(20 * time.Millisecond)
+
+				if !display.IsPaused() {
+					resumed.Add(1)
+				}
+
+				running.Add(-1)
+
+				return nil
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	require.Zero(t, overlaps.Load())
+	require.Zero(t, resumed.Load())
+	require.False(t, display.IsPaused())
+	require.NoError(t, display.Close())
+}
+
+func Test_PromptForm_WithAnsweredForm_FillsValues(t *testing.T) {
+	var buf, formOutput bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	var name string
+
+	form := huh.NewForm(huh.NewGroup(huh.NewInput().Title("Name").Value(&name))).
+		WithAccessible(true).
+		WithInput(strings.NewReader("Alice\n")).
+		WithOutput(&formOutput)
+
+	require.NoError(t, display.PromptForm(form))
+	require.Equal(t, "Alice", name)
+	require.False(t, display.IsPaused())
+}
+
+func Test_Confirm_WithYes_ReturnsTrueAndRecordsAnswer(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("y\n")))
+
+	_ = display.Start("Installing")
+
+	confirmed, err := display.Confirm("Overwrite config?")
+
+	require.NoError(t, err)
+	require.True(t, confirmed)
+	require.False(t, display.IsPaused())
+	require.Contains(t, display.GetOutputSafely(), "? Overwrite config? yes\n")
+}
+
+func Test_Confirm_WithResultOutput_RecordsAnswerInResultOutput(t *testing.T) {
+	var buf, results bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf,
+		nesgress.WithPromptInput(strings.NewReader("y\n")),
+		nesgress.WithResultOutput(&results),
+	)
+
+	_, err := display.Confirm("Overwrite config?")
+
+	require.NoError(t, err)
+	require.Contains(t, results.String(), "? Overwrite config? yes\n")
+	require.NotContains(t, buf.String(), "Overwrite config? yes")
+}
+
+func Test_Confirm_WithConcurrentQuestions_AsksThemOneAtATime(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("y\nn\ny\nn\n")))
+
+	var (
+		confirmed atomic.Int32
+		failed    atomic.Int32
+		wg        sync.WaitGroup
+	)
+
+	for range 4 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			answer, err := display.Confirm("Overwrite config?")
+			if err != nil {
+				failed.Add(1)
+			} else if answer {
+				confirmed.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.Zero(t, failed.Load())
+	require.Equal(t, int32(2), confirmed.Load())
+	require.Equal(t, 2, strings.Count(display.GetOutputSafely(), "? Overwrite config? yes\n"))
+	require.Equal(t, 2, strings.Count(display.GetOutputSafely(), "? Overwrite config? no\n"))
+}
+
+func Test_Confirm_WithEmptyReply_DefaultsToNo(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("\n")))
+
+	confirmed, err := display.Confirm("Overwrite config?")
+
+	require.NoError(t, err)
+	require.False(t, confirmed)
+	require.Contains(t, display.GetOutputSafely(), "? Overwrite config? no\n")
+}
+
+func Test_Confirm_WithInvalidReply_AsksAgain(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("maybe\nyes\n")))
+
+	confirmed, err := display.Confirm("Overwrite config?")
+
+	require.NoError(t, err)
+	require.True(t, confirmed)
+	require.Contains(t, display.GetOutputSafely(), "answer yes or no")
+}
+
+func Test_Confirm_WithoutReply_ReturnsError(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("")))
+
+	_, err := display.Confirm("Overwrite config?")
+
+	require.ErrorIs(t, err, io.EOF)
+	require.False(t, display.IsPaused())
+}
+
+func Test_Confirm_InPersistentOperation_IndentsPromptUnderIt(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("y\n")))
+
+	_ = display.StartPersistent("Deploying")
+	_ = display.StartPersistent("Migrating")
+
+	_, err := display.Confirm("Continue?")
+
+	require.NoError(t, err)
+	require.Contains(t, display.GetOutputSafely(), "      ? Continue? yes\n")
+}
+
+func Test_Input_WithEmptyReply_ReturnsDefault(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("\n")))
+
+	value, err := display.Input("Install directory", nesgress.InputOptions{Default: "/opt/tool"})
+
+	require.NoError(t, err)
+	require.Equal(t, "/opt/tool", value)
+	require.Contains(t, display.GetOutputSafely(), "? Install directory (/opt/tool)")
+	require.Contains(t, display.GetOutputSafely(), "? Install directory /opt/tool\n")
+}
+
+func Test_Input_WithRejectedReply_AsksUntilValid(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("abc\n8080\n")))
+
+	value, err := display.Input("Port", nesgress.InputOptions{
+		Validate: func(reply string) error {
+			if _, err := strconv.Atoi(reply); err != nil {
+				return errors.New("not a number")
+			}
+
+			return nil
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "8080", value)
+	require.Contains(t, display.GetOutputSafely(), "not a number")
+	require.Contains(t, display.GetOutputSafely(), "? Port 8080\n")
+}
+
+func Test_Input_WhenMasked_KeepsAnswerOutOfOutput(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("hunter2\n")))
+
+	value, err := display.Input("Password", nesgress.InputOptions{Masked: true})
+
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", value)
+	require.NotContains(t, display.GetOutputSafely(), "hunter2")
+	require.Contains(t, display.GetOutputSafely(), "? Password ********\n")
+}
+
+func Test_Select_WithNumber_ReturnsChoice(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("2\n")))
+
+	choice, err := display.Select("Shell", []string{"bash", "zsh", "fish"})
+
+	require.NoError(t, err)
+	require.Equal(t, "zsh", choice)
+	require.Contains(t, display.GetOutputSafely(), "1) bash")
+	require.Contains(t, display.GetOutputSafely(), "3) fish")
+	require.Contains(t, display.GetOutputSafely(), "? Shell zsh\n")
+}
+
+func Test_Select_WithChoiceText_ReturnsChoice(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("4\nFish\n")))
+
+	choice, err := display.Select("Shell", []string{"bash", "zsh", "fish"})
+
+	require.NoError(t, err)
+	require.Equal(t, "fish", choice)
+	require.Contains(t, display.GetOutputSafely(), "choose a number from 1 to 3")
+}
+
+func Test_Select_WithoutChoices_ReturnsError(t *testing.T) {
+	display := nesgress.NewProgressDisplay(&bytes.Buffer{})
+
+	_, err := display.Select("Shell", nil)
+
+	require.Error(t, err)
+}
+
+func Test_Prompts_InSequence_ReadFromSameInput(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithPromptInput(strings.NewReader("alice\ny\n")))
+
+	name, err := display.Input("Name", nesgress.InputOptions{})
+	require.NoError(t, err)
+
+	confirmed, err := display.Confirm("Continue?")
+	require.NoError(t, err)
+
+	require.Equal(t, "alice", name)
+	require.True(t, confirmed)
+}
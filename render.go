@@ -0,0 +1,146 @@
+package nesgress
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// frameInterval is how often the live line is redrawn.
+const frameInterval = 100 * time.Millisecond
+
+// Styles of the live line.
+var (
+	spinnerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F780E2"))
+	titleStyle   = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00020A", Dark: "#FFFDF5"})
+)
+
+// activeOperation returns the operation shown on the live line, or nil if nothing should be drawn:
+// the innermost operation, unless it already completed or the display is paused.
+func (p *ProgressDisplay) activeOperation() *ProgressOperation {
+	if p.IsPaused() {
+		return nil
+	}
+
+	operation := p.currentOperation()
+	if operation == nil || operation.IsDone() {
+		return nil
+	}
+
+	return operation
+}
+
+// refresh brings the live line in line with the operations after they changed.
+// The render loop is started when there is an operation to draw,
+// otherwise the last frame is cleared and the cursor restored right away.
+func (p *ProgressDisplay) refresh() error {
+	p.renderMutex.Lock()
+	defer p.renderMutex.Unlock()
+
+	// Embedding models redraw on their own
+	if p.embedded != nil {
+		p.embedded.signal()
+		return nil
+	}
+
+	if p.activeOperation() == nil {
+		return p.clearFrame()
+	}
+
+	if !p.rendering {
+		p.rendering = true
+
+		go p.renderLoop()
+	}
+
+	return nil
+}
+
+// renderLoop draws frames at a fixed rate until there is nothing left to draw.
+// It's the only goroutine drawing on the live line, so operations are started and completed
+// by updating the stack alone, and bursts of changes are coalesced to the frame rate.
+func (p *ProgressDisplay) renderLoop() {
+	ticker := time.NewTicker(frameInterval)
+	defer ticker.Stop()
+
+	stopWatchingWidth := p.watchWidth()
+	defer stopWatchingWidth()
+
+	for p.renderFrame() {
+		<-ticker.C
+	}
+}
+
+// renderFrame draws the next frame of the active operation, and reports whether there still is one.
+// When there is none, the render loop is marked as stopped.
+func (p *ProgressDisplay) renderFrame() bool {
+	p.renderMutex.Lock()
+	defer p.renderMutex.Unlock()
+
+	// Looked up while holding renderMutex, so a completion line never gets followed by a stale frame
+	operation := p.activeOperation()
+	if operation == nil || p.embedded != nil {
+		p.rendering = false
+
+		//nolint:errcheck // Nobody to report display errors to from the render loop
+		_ = p.clearFrame()
+
+		return false
+	}
+
+	p.checkStall(operation)
+
+	// Accessible mode repeats the status of long operations instead of animating it
+	if p.accessible {
+		p.announceStatus(operation)
+
+		return true
+	}
+
+	// Operations within their start delay leave the spinner to their ancestors
+	operation = p.visibleOperation(operation)
+	if operation == nil {
+		return true
+	}
+
+	if p.cursorHidden.CompareAndSwap(0, 1) {
+		_, _ = fmt.Fprint(p.output, hideCursor)
+	}
+
+	spinner := spinnerStyle.Render(p.glyphs.spinnerFrame(p.frame))
+	title := titleStyle.Render(p.spinnerTitle(operation, p.titleWidth()))
+
+	_, _ = fmt.Fprintf(p.output, "\r%s %s%s", spinner, title, clearLine)
+
+	p.frame++
+	p.frameDrawn = true
+
+	return true
+}
+
+// visibleOperation returns the innermost of an operation and its running ancestors
+// that has been running for at least the display's start delay, or nil if none has.
+func (p *ProgressDisplay) visibleOperation(operation *ProgressOperation) *ProgressOperation {
+	for current := operation; current != nil; current = current.parent {
+		if !current.IsDone() && time.Since(current.StartTime) >= p.startDelay {
+			return current
+		}
+	}
+
+	return nil
+}
+
+// clearFrame removes the last frame from the live line and restores the cursor.
+// Note: This method assumes the caller holds renderMutex.
+func (p *ProgressDisplay) clearFrame() error {
+	if p.frameDrawn {
+		p.frameDrawn = false
+
+		if _, err := fmt.Fprint(p.output, "\r"+clearLine); err != nil {
+			return err
+		}
+	}
+
+	return p.restoreCursor()
+}
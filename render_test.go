@@ -0,0 +1,174 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_LastOperationCompleted_AfterFramesWereDrawn_RestoresCursor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Compiling")
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(display.GetOutputSafely(), "Compiling")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_ = display.Finish("Compiling")
+
+	// Finish returns only once the terminal is left in order, without waiting for the next frame
+	output := buf.String()
+	require.Contains(t, output, "\033[?25l")
+	require.Greater(t, strings.LastIndex(output, "\033[?25h"), strings.LastIndex(output, "\033[?25l"))
+	require.False(t, display.IsActive())
+}
+
+func Test_RenderLoop_WithNestedOperations_DrawsOnlyInnermost(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Installing")
+	_ = display.Start("Downloading")
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(display.GetOutputSafely(), "Installing: Downloading")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_ = display.Finish("Downloading")
+
+	lines := completionLines(display.GetOutputSafely())
+	require.Equal(t, "✓ Downloading", lines[len(lines)-1])
+
+	_ = display.Finish("Installing")
+	require.NoError(t, display.Close())
+}
+
+func Test_ConcurrentStartAndFinish_FromManyGoroutines_DoesNotPanic(t *testing.T) {
+	display := nesgress.NewProgressDisplay(io.Discard)
+
+	var wg sync.WaitGroup
+
+	for range 16 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 200 {
+				_ = display.Start("Working")
+				_ = display.Update("Working harder")
+				_ = display.Finish("Worked")
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.False(t, display.IsActive())
+	require.NoError(t, display.Close())
+}
+
+func Benchmark_ShortOperations_InTightLoop(b *testing.B) {
+	display := nesgress.NewProgressDisplay(io.Discard)
+	defer display.Close()
+
+	for b.Loop() {
+		_ = display.Start("Processing item")
+		_ = display.Finish("Processed item")
+	}
+}
+
+func Benchmark_NestedShortOperations_InTightLoop(b *testing.B) {
+	display := nesgress.NewProgressDisplay(io.Discard)
+	defer display.Close()
+
+	_ = display.StartPersistent("Processing items")
+
+	for b.Loop() {
+		_ = display.Start("Processing item")
+		_ = display.Update("Processing item, almost done")
+		_ = display.Finish("Processed item")
+	}
+
+	_ = display.FinishPersistent("Processed items")
+}
+
+func Test_StartDelay_WithFastOperations_DrawsNoSpinnerFrames(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithStartDelay(time.Hour))
+
+	for range 3 {
+		_ = display.Start("Checking")
+		_ = display.Finish("Checking")
+	}
+
+	require.NoError(t, display.Close())
+
+	output := buf.String()
+	require.NotContains(t, output, "\033[?25l")
+	require.NotContains(t, output, "\033[?25h")
+	require.Equal(t, []string{"✓ Checking", "✓ Checking", "✓ Checking"}, completionLines(output))
+}
+
+func Test_StartDelay_WithSlowOperation_DrawsSpinnerOnceDelayPassed(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithStartDelay(300*time.Millisecond))
+
+	_ = display.Start("Compiling")
+	require.Empty(t, display.GetOutputSafely())
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(display.GetOutputSafely(), "Compiling")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_ = display.Finish("Compiling")
+	require.NoError(t, display.Close())
+}
+
+func Test_StartDelay_WithNestedOperationWithinDelay_KeepsDrawingParent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithStartDelay(300*time.Millisecond))
+
+	_ = display.Start("Installing")
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(display.GetOutputSafely(), "Installing")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_ = display.Start("Linking")
+	time.Sleep(150 * time.Millisecond)
+
+	output := display.GetOutputSafely()
+	latestFrame := output[strings.LastIndex(output, "\r"):]
+	require.Contains(t, latestFrame, "Installing")
+	require.NotContains(t, latestFrame, "Linking")
+
+	_ = display.Finish("Linking")
+	_ = display.Finish("Installing")
+	require.NoError(t, display.Close())
+}
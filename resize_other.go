@@ -0,0 +1,33 @@
+//go:build !unix
+
+package nesgress
+
+import (
+	"time"
+)
+
+// resizePollInterval is how often the width of the terminal is measured where size changes aren't signaled.
+const resizePollInterval = time.Second
+
+// watchResize measures the width of the display's terminal periodically, as there's no SIGWINCH to wait for.
+// It returns a function stopping the watch.
+func (p *ProgressDisplay) watchResize() func() {
+	ticker := time.NewTicker(resizePollInterval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				p.measureWidth()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
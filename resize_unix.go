@@ -0,0 +1,34 @@
+//go:build unix
+
+package nesgress
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchResize measures the width of the display's terminal again whenever it signals a size change with SIGWINCH.
+// It returns a function stopping the watch.
+func (p *ProgressDisplay) watchResize() func() {
+	resized := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	signal.Notify(resized, syscall.SIGWINCH)
+
+	go func() {
+		for {
+			select {
+			case <-resized:
+				p.measureWidth()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(resized)
+		close(done)
+	}
+}
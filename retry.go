@@ -0,0 +1,125 @@
+package nesgress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultBackoffMultiplier is the growth factor of retry delays when the policy doesn't set one.
+const defaultBackoffMultiplier = 2
+
+// RetryPolicy controls how Retry repeats a failing function.
+type RetryPolicy struct {
+	// Retryable reports whether an error is worth another attempt, nil retries every error
+	Retryable func(err error) bool
+	// Attempts is the total number of attempts, including the first one
+	Attempts int
+	// InitialDelay is the backoff before the second attempt
+	InitialDelay time.Duration
+	// MaxDelay caps the backoff between attempts, zero means no cap
+	MaxDelay time.Duration
+	// Multiplier grows the backoff after every attempt, zero means doubling it
+	Multiplier float64
+}
+
+// nextDelay returns the backoff following the given one.
+func (policy RetryPolicy) nextDelay(delay time.Duration) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier == 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+
+	delay = time.Duration(float64(delay) * multiplier)
+	if policy.MaxDelay > 0 {
+		delay = min(delay, policy.MaxDelay)
+	}
+
+	return delay
+}
+
+// Retry runs fn under a single progress operation until it succeeds, the policy gives up, or ctx ends.
+//
+// From the second attempt on, the operation's message shows the attempt number,
+// and counts down the backoff between attempts. Failed attempts are logged beneath the operation
+// on reporters implementing Logger. The operation completes with its original message.
+// If no attempt succeeds, the operation fails with the errors of all attempts joined,
+// along with the cause of ctx if it ended.
+//
+// Like the reporter's own methods, display errors are ignored, and only fn's outcome is returned.
+func Retry(
+	ctx context.Context,
+	reporter ProgressReporter,
+	message string,
+	policy RetryPolicy,
+	fn func(attempt int) error,
+) error {
+	attempts := max(policy.Attempts, 1)
+	delay := policy.InitialDelay
+	relabeled := false // whether the operation's message shows attempts or backoffs
+
+	var errs []error
+
+	_ = reporter.Start(message)
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if cause := context.Cause(ctx); cause != nil {
+			errs = append(errs, cause)
+			break
+		}
+
+		if attempt > 1 {
+			_ = reporter.Update(fmt.Sprintf("%s (attempt %d/%d)", message, attempt, attempts))
+			relabeled = true
+		}
+
+		err := fn(attempt)
+		if err == nil {
+			if relabeled {
+				_ = reporter.Update(message)
+			}
+
+			_ = reporter.Finish(message)
+
+			return nil
+		}
+
+		errs = append(errs, fmt.Errorf("attempt %d: %w", attempt, err))
+
+		if attempt == attempts || (policy.Retryable != nil && !policy.Retryable(err)) {
+			break
+		}
+
+		_ = logLine(reporter, fmt.Sprintf("attempt %d/%d failed: %v", attempt, attempts, err))
+
+		relabeled = relabeled || delay > 0
+		backoff(ctx, reporter, message, fmt.Sprintf("attempt %d/%d failed", attempt, attempts), delay)
+		delay = policy.nextDelay(delay)
+	}
+
+	if relabeled {
+		_ = reporter.Update(message)
+	}
+
+	err := errors.Join(errs...)
+	_ = reporter.Fail(message, err)
+
+	return err
+}
+
+// backoff waits for the given delay, counting it down in the operation's message every second.
+// It stops waiting as soon as ctx ends.
+func backoff(ctx context.Context, reporter ProgressReporter, message, status string, delay time.Duration) {
+	deadline := time.Now().Add(delay)
+
+	for remaining := delay; remaining > 0; remaining = time.Until(deadline) {
+		_ = reporter.Update(fmt.Sprintf("%s (%s, retrying in %v)", message, status, roundUpToSecond(remaining)))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(min(remaining, time.Second)):
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+// recordingReporter records the calls made to a reporter.
+type recordingReporter struct {
+	failErr error
+	calls   []string
+
+	nesgress.NoopProgressDisplay
+}
+
+func (r *recordingReporter) Start(message string) error {
+	r.calls = append(r.calls, "start: "+message)
+	return nil
+}
+
+func (r *recordingReporter) Update(message string) error {
+	r.calls = append(r.calls, "update: "+message)
+	return nil
+}
+
+func (r *recordingReporter) Log(message string) error {
+	r.calls = append(r.calls, "log: "+message)
+	return nil
+}
+
+func (r *recordingReporter) LogAccomplishment(message string) error {
+	r.calls = append(r.calls, "accomplishment: "+message)
+	return nil
+}
+
+func (r *recordingReporter) Finish(message string) error {
+	r.calls = append(r.calls, "finish: "+message)
+	return nil
+}
+
+func (r *recordingReporter) Fail(message string, err error) error {
+	r.calls = append(r.calls, "fail: "+message)
+	r.failErr = err
+
+	return nil
+}
+
+func Test_Retry_SucceedingOnLaterAttempt_ShowsAttemptsAndFinishes(t *testing.T) {
+	reporter := &recordingReporter{}
+
+	err := nesgress.Retry(context.Background(), reporter, "Fetching", nesgress.RetryPolicy{Attempts: 3}, func(attempt int) error {
+		if attempt < 2 {
+			return errors.New("connection reset")
+		}
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"start: Fetching",
+		"log: attempt 1/3 failed: connection reset",
+		"update: Fetching (attempt 2/3)",
+		"update: Fetching",
+		"finish: Fetching",
+	}, reporter.calls)
+}
+
+func Test_Retry_WhenEveryAttemptFails_FailsWithAllErrorsJoined(t *testing.T) {
+	reporter := &recordingReporter{}
+	first := errors.New("first")
+	second := errors.New("second")
+	attemptErrors := []error{first, second}
+
+	err := nesgress.Retry(context.Background(), reporter, "Fetching", nesgress.RetryPolicy{Attempts: 2}, func(attempt int) error {
+		return attemptErrors[attempt-1]
+	})
+
+	require.ErrorIs(t, err, first)
+	require.ErrorIs(t, err, second)
+	require.Equal(t, err, reporter.failErr)
+	require.Equal(t, "fail: Fetching", reporter.calls[len(reporter.calls)-1])
+}
+
+func Test_Retry_WithNonRetryableError_StopsImmediately(t *testing.T) {
+	reporter := &recordingReporter{}
+	permanent := errors.New("unauthorized")
+	attempts := 0
+
+	policy := nesgress.RetryPolicy{
+		Attempts: 5,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, permanent)
+		},
+	}
+
+	err := nesgress.Retry(context.Background(), reporter, "Fetching", policy, func(int) error {
+		attempts++
+		return permanent
+	})
+
+	require.ErrorIs(t, err, permanent)
+	require.Equal(t, 1, attempts)
+}
+
+func Test_Retry_WithBackoff_CountsDownBetweenAttempts(t *testing.T) {
+	reporter := &recordingReporter{}
+	policy := nesgress.RetryPolicy{Attempts: 2, InitialDelay: 1500 * time.Millisecond}
+
+	start := time.Now()
+	_ = nesgress.Retry(context.Background(), reporter, "Fetching", policy, func(int) error {
+		return errors.New("busy")
+	})
+
+	require.GreaterOrEqual(t, time.Since(start), 1500*time.Millisecond)
+	require.Contains(t, reporter.calls, "update: Fetching (attempt 1/2 failed, retrying in 2s)")
+	require.Contains(t, reporter.calls, "update: Fetching (attempt 1/2 failed, retrying in 1s)")
+}
+
+func Test_RetryPolicy_WithMaxDelay_CapsBackoff(t *testing.T) {
+	reporter := &recordingReporter{}
+	policy := nesgress.RetryPolicy{Attempts: 3, InitialDelay: 10 * time.Millisecond, MaxDelay: 15 * time.Millisecond}
+
+	start := time.Now()
+	_ = nesgress.Retry(context.Background(), reporter, "Fetching", policy, func(int) error {
+		return errors.New("busy")
+	})
+
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func Test_Retry_WithProgressDisplay_LogsFailedAttemptsBeneathOperation(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = nesgress.Retry(context.Background(), display, "Fetching", nesgress.RetryPolicy{Attempts: 2}, func(attempt int) error {
+		if attempt == 1 {
+			return errors.New("connection reset")
+		}
+
+		return nil
+	})
+
+	lines := completionLines(display.GetOutputSafely())
+	require.Equal(t, []string{
+		"   attempt 1/2 failed: connection reset",
+		"✓ Fetching",
+	}, lines)
+}
+
+func Test_Retry_WhenContextEndsDuringBackoff_StopsWaitingAndFails(t *testing.T) {
+	reporter := &recordingReporter{}
+	policy := nesgress.RetryPolicy{Attempts: 2, InitialDelay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := nesgress.Retry(ctx, reporter, "Fetching", policy, func(int) error {
+		return errors.New("busy")
+	})
+
+	require.Less(t, time.Since(start), time.Second)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, []string{"update: Fetching", "fail: Fetching"}, reporter.calls[len(reporter.calls)-2:])
+}
@@ -0,0 +1,79 @@
+package nesgress
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StallHandler is called when an operation made no progress within its stall threshold.
+// It receives the contextual message of the operation and how long it has been idle.
+type StallHandler func(message string, idle time.Duration)
+
+// SetStallThreshold overrides the display's stall threshold for the current operation.
+// A zero threshold falls back to the display's threshold, a negative one disables stall detection.
+func (p *ProgressDisplay) SetStallThreshold(threshold time.Duration) error {
+	p.stackMutex.RLock()
+	defer p.stackMutex.RUnlock()
+
+	if len(p.progressStack) == 0 {
+		return nil
+	}
+
+	p.progressStack[len(p.progressStack)-1].stallThreshold.Store(int64(threshold))
+
+	return nil
+}
+
+// touch records progress on the operation, clearing any stall.
+func (op *ProgressOperation) touch() {
+	op.lastProgress.Store(time.Now().UnixNano())
+	op.stallReported.Store(false)
+}
+
+// idleFor returns how long the operation has been stalled, or zero if it isn't.
+func (p *ProgressDisplay) idleFor(operation *ProgressOperation) time.Duration {
+	threshold := time.Duration(operation.stallThreshold.Load())
+	if threshold == 0 {
+		threshold = p.stallThreshold
+	}
+
+	if threshold <= 0 {
+		return 0
+	}
+
+	idle := time.Since(time.Unix(0, operation.lastProgress.Load()))
+	if idle < threshold {
+		return 0
+	}
+
+	return idle
+}
+
+// checkStall notifies the stall handler once each time the operation stalls.
+func (p *ProgressDisplay) checkStall(operation *ProgressOperation) {
+	if p.stallHandler == nil {
+		return
+	}
+
+	idle := p.idleFor(operation)
+	if idle == 0 || !operation.stallReported.CompareAndSwap(false, true) {
+		return
+	}
+
+	// Handlers may be slow, e.g. when dumping diagnostics, and must not hold up the spinner
+	go p.stallHandler(p.contextualMessage(operation), idle)
+}
+
+// stallWarning returns the warning shown next to a stalled operation, or an empty string.
+func (p *ProgressDisplay) stallWarning(operation *ProgressOperation) string {
+	idle := p.idleFor(operation)
+	if idle == 0 {
+		return ""
+	}
+
+	warning := fmt.Sprintf("no progress for %v", roundLiveDuration(idle))
+
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#f39c12")).Render(warning)
+}
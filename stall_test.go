@@ -0,0 +1,101 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_StallDetection_WithoutProgress_CallsHandlerOnce(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var (
+		buf      bytes.Buffer
+		calls    atomic.Int32
+		stalled  atomic.Value
+		lastIdle atomic.Int64
+	)
+
+	display := nesgress.NewProgressDisplay(&buf,
+		nesgress.WithStallThreshold(50*time.Millisecond),
+		nesgress.WithStallHandler(func(message string, idle time.Duration) {
+			calls.Add(1)
+			stalled.Store(message)
+			lastIdle.Store(int64(idle))
+		}),
+	)
+
+	_ = display.Start("Parent")
+	_ = display.Start("Waiting")
+	time.Sleep(200 * time.Millisecond)
+
+	require.Equal(t, int32(1), calls.Load())
+	require.Equal(t, "Parent: Waiting", stalled.Load())
+	require.GreaterOrEqual(t, time.Duration(lastIdle.Load()), 50*time.Millisecond)
+	require.Contains(t, display.GetOutputSafely(), "no progress for")
+
+	_ = display.Finish("Waiting")
+	_ = display.Finish("Parent")
+}
+
+func Test_StallDetection_WithRegularUpdates_DoesNotStall(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var (
+		buf   bytes.Buffer
+		calls atomic.Int32
+	)
+
+	display := nesgress.NewProgressDisplay(&buf,
+		nesgress.WithStallThreshold(100*time.Millisecond),
+		nesgress.WithStallHandler(func(string, time.Duration) {
+			calls.Add(1)
+		}),
+	)
+
+	_ = display.Start("Downloading")
+
+	for range 6 {
+		time.Sleep(30 * time.Millisecond)
+		_ = display.Update("Downloading")
+	}
+
+	_ = display.Finish("Downloading")
+
+	require.Zero(t, calls.Load())
+	require.NotContains(t, display.GetOutputSafely(), "no progress for")
+}
+
+func Test_StallDetection_WithPerOperationThreshold_OverridesDisplayThreshold(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var (
+		buf   bytes.Buffer
+		calls atomic.Int32
+	)
+
+	display := nesgress.NewProgressDisplay(&buf,
+		nesgress.WithStallThreshold(50*time.Millisecond),
+		nesgress.WithStallHandler(func(string, time.Duration) {
+			calls.Add(1)
+		}),
+	)
+
+	_ = display.Start("Slow but expected")
+	_ = display.SetStallThreshold(-1)
+	time.Sleep(150 * time.Millisecond)
+	_ = display.Finish("Slow but expected")
+
+	require.Zero(t, calls.Load())
+}
@@ -0,0 +1,220 @@
+package nesgress
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TeaModel is a Bubble Tea model rendering the running operations of a progress display,
+// for tools that are Bubble Tea programs themselves and can't share the terminal with the display.
+//
+// Once embedded, the display stops writing to its outputs. Its reporter calls, made from any goroutine,
+// are delivered to the program as messages instead: permanent lines such as completions and accomplishments
+// are printed above the program, and View renders the running operations as a tree.
+// Like any nested model, it must receive all messages through its Update, starting with the command of Init.
+type TeaModel struct {
+	display *ProgressDisplay
+	outbox  *teaOutbox
+	frame   int
+}
+
+// NewTeaModel embeds the given display in a Bubble Tea model.
+//
+//	display := nesgress.NewProgressDisplay(nil)
+//	progress := nesgress.NewTeaModel(display)
+//	go install(display)
+func NewTeaModel(display *ProgressDisplay) *TeaModel {
+	outbox := newTeaOutbox()
+	display.embed(outbox)
+
+	return &TeaModel{display: display, outbox: outbox}
+}
+
+// teaFrameMsg advances the spinner of the model owning the outbox.
+type teaFrameMsg struct {
+	outbox *teaOutbox
+}
+
+// teaOutboxMsg delivers what a display printed since the last message, and tells its model to redraw.
+type teaOutboxMsg struct {
+	outbox *teaOutbox
+	lines  []string
+	closed bool
+}
+
+// Init starts the spinner and the delivery of the display's output.
+func (m *TeaModel) Init() tea.Cmd {
+	return tea.Batch(m.nextFrame(), m.outbox.receive)
+}
+
+// Update handles the messages of the model's display and ignores all others.
+func (m *TeaModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case teaFrameMsg:
+		if msg.outbox != m.outbox || m.outbox.isClosed() {
+			return m, nil
+		}
+
+		m.frame++
+
+		// There's no render loop checking for stalls while embedded
+		if operation := m.display.activeOperation(); operation != nil {
+			m.display.checkStall(operation)
+		}
+
+		return m, m.nextFrame()
+	case teaOutboxMsg:
+		if msg.outbox != m.outbox {
+			return m, nil
+		}
+
+		cmds := make([]tea.Cmd, 0, len(msg.lines)+1)
+		for _, line := range msg.lines {
+			cmds = append(cmds, tea.Println(line))
+		}
+
+		if !msg.closed {
+			cmds = append(cmds, m.outbox.receive)
+		}
+
+		return m, tea.Sequence(cmds...)
+	}
+
+	return m, nil
+}
+
+// View renders the running operations as a tree, with a spinner in front of the innermost one.
+func (m *TeaModel) View() string {
+	return m.display.renderLiveTree(m.frame)
+}
+
+// nextFrame schedules the next spinner frame.
+func (m *TeaModel) nextFrame() tea.Cmd {
+	return tea.Tick(frameInterval, func(time.Time) tea.Msg {
+		return teaFrameMsg{outbox: m.outbox}
+	})
+}
+
+// teaOutbox passes what an embedded display prints on to its TeaModel, without blocking reporter calls.
+type teaOutbox struct {
+	ready  chan struct{} // holds a signal while output waits to be delivered
+	lines  []string
+	mutex  sync.Mutex
+	closed bool
+}
+
+// newTeaOutbox creates an empty outbox.
+func newTeaOutbox() *teaOutbox {
+	return &teaOutbox{ready: make(chan struct{}, 1)}
+}
+
+// print queues a permanent line.
+func (o *teaOutbox) print(line string) {
+	o.mutex.Lock()
+	o.lines = append(o.lines, line)
+	o.mutex.Unlock()
+
+	o.signal()
+}
+
+// signal tells the model to redraw, coalescing with signals not received yet.
+func (o *teaOutbox) signal() {
+	select {
+	case o.ready <- struct{}{}:
+	default:
+	}
+}
+
+// close delivers the remaining lines and ends the delivery.
+func (o *teaOutbox) close() {
+	o.mutex.Lock()
+	o.closed = true
+	o.mutex.Unlock()
+
+	o.signal()
+}
+
+// isClosed reports whether the delivery ended.
+func (o *teaOutbox) isClosed() bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.closed
+}
+
+// receive waits for output and returns it as a message. It's run as a command by the model.
+func (o *teaOutbox) receive() tea.Msg {
+	<-o.ready
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	msg := teaOutboxMsg{outbox: o, lines: o.lines, closed: o.closed}
+	o.lines = nil
+
+	return msg
+}
+
+// embed hands the display's output over to the given outbox, clearing anything drawn so far.
+func (p *ProgressDisplay) embed(outbox *teaOutbox) {
+	p.renderMutex.Lock()
+	defer p.renderMutex.Unlock()
+
+	//nolint:errcheck // The terminal belongs to the Bubble Tea program from now on
+	_ = p.clearFrame()
+
+	p.embedded = outbox
+}
+
+// renderLiveTree renders the running operations as a chain of nested tree nodes,
+// with their live status and a spinner in front of the innermost one.
+func (p *ProgressDisplay) renderLiveTree(frame int) string {
+	if p.IsPaused() {
+		return ""
+	}
+
+	p.stackMutex.RLock()
+	operations := slices.Clone(p.progressStack)
+
+	messages := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		messages = append(messages, operation.Message)
+	}
+	p.stackMutex.RUnlock()
+
+	var builder strings.Builder
+
+	depth := 0
+
+	for i, operation := range operations {
+		// Timed out operations wait for their completion without being drawn
+		if operation.IsDone() {
+			continue
+		}
+
+		line := messages[i]
+		if label := p.elapsedLabel(operation); label != "" {
+			line += " " + label
+		}
+
+		line = titleStyle.Render(line + p.liveStatus(operation))
+
+		if i == len(operations)-1 {
+			line = spinnerStyle.Render(p.glyphs.spinnerFrame(frame)) + " " + line
+		}
+
+		if depth > 0 {
+			builder.WriteString(strings.Repeat("   ", depth-1) + p.glyphs.lastBranch)
+		}
+
+		builder.WriteString(line + "\n")
+
+		depth++
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
@@ -0,0 +1,101 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+// lockedBuffer is a bytes.Buffer safe for a program writing to it while a test reads it.
+type lockedBuffer struct {
+	buf   bytes.Buffer
+	mutex sync.Mutex
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.String()
+}
+
+func Test_TeaModel_View_RendersRunningOperationsAsTree(t *testing.T) {
+	var buf bytes.Buffer
+
+	display := nesgress.NewProgressDisplay(&buf)
+	model := nesgress.NewTeaModel(display)
+
+	_ = display.Start("Installing")
+	_ = display.Start("Downloading")
+	_ = display.Update("Downloading zlib")
+
+	require.Equal(t, "Installing\n└─ ⣾ Downloading zlib", model.View())
+
+	_ = display.Finish("Downloading")
+	require.Equal(t, "⣾ Installing", model.View())
+
+	_ = display.Finish("Installing")
+	require.Empty(t, model.View())
+	require.Empty(t, buf.String())
+}
+
+func Test_TeaModel_InProgram_PrintsPermanentLinesAboveView(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+
+	display := nesgress.NewProgressDisplay(&buf)
+	model := nesgress.NewTeaModel(display)
+
+	var output lockedBuffer
+
+	program := tea.NewProgram(model, tea.WithInput(nil), tea.WithOutput(&output), tea.WithoutSignalHandler())
+
+	done := make(chan error)
+
+	go func() {
+		_, err := program.Run()
+		done <- err
+	}()
+
+	go func() {
+		_ = display.Start("Installing")
+		_ = display.LogAccomplishment("Fetched zlib")
+		_ = display.Start("Linking")
+		_ = display.Fail("Linking", errors.New("missing symbol"))
+		_ = display.Finish("Installing")
+	}()
+
+	require.Eventually(t, func() bool {
+		rendered := output.String()
+
+		return strings.Contains(rendered, "✓ Fetched zlib") &&
+			strings.Contains(rendered, "✗ Linking") &&
+			strings.Contains(rendered, "Error: missing symbol") &&
+			strings.Contains(rendered, "✓ Installing")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, display.Close())
+	program.Quit()
+	require.NoError(t, <-done)
+
+	// The display left the terminal to the program
+	require.Empty(t, buf.String())
+}
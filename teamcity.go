@@ -0,0 +1,94 @@
+package nesgress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// teamcityEscaper escapes the values of TeamCity service messages.
+var teamcityEscaper = strings.NewReplacer(
+	"|", "||",
+	"'", "|'",
+	"\n", "|n",
+	"\r", "|r",
+	"[", "|[",
+	"]", "|]",
+	"\u0085", "|x",
+	"\u2028", "|l",
+	"\u2029", "|p",
+)
+
+// teamcityDialect writes progress as plain text with TeamCity service messages.
+type teamcityDialect struct {
+	blocks []teamcityBlock // blocks opened and not yet closed, outermost first
+}
+
+// teamcityBlock is a block of the TeamCity build log.
+type teamcityBlock struct {
+	name  string // name the block opened with, which must close it even if the operation's message changed
+	level int    // nesting level of the operation the block wraps
+}
+
+// TeamCityDialect writes progress like PlainDialect, wrapping every operation in a block of the TeamCity build log,
+// reporting updates as progress messages of the build, and failures as build problems:
+//
+//	##teamcity[blockOpened name='Installing packages']
+//	##teamcity[blockOpened name='Downloading zlib']
+//	##teamcity[blockClosed name='Downloading zlib']
+//	  [ok] Downloading zlib (took 1.2s)
+//	##teamcity[blockClosed name='Installing packages']
+//	[ok] Installing packages (took 3s)
+func TeamCityDialect() Dialect {
+	return &teamcityDialect{}
+}
+
+// WriteEvent writes the lines of an event, along with the service messages it calls for.
+func (t *teamcityDialect) WriteEvent(w io.Writer, event Event) error {
+	switch event.Type {
+	case EventStart:
+		t.blocks = append(t.blocks, teamcityBlock{name: event.Operation.Message, level: event.Operation.Level})
+
+		// The block's name stands in for the start line
+		return writeLines(w, teamcityMessage("blockOpened", "name", event.Operation.Message))
+	case EventUpdate:
+		return writeLines(w,
+			plainText(event),
+			teamcityMessage("progressMessage", "", event.Operation.Message),
+		)
+	case EventFinish, EventCancel:
+		return writeLines(w, append(t.closeBlocks(event.Operation.Level), plainText(event))...)
+	case EventFail:
+		problem := strings.Join(append(operationPath(event), failureMessage(event.Operation)), contextSeparator)
+
+		lines := t.closeBlocks(event.Operation.Level)
+		lines = append(lines, teamcityMessage("buildProblem", "description", problem), plainText(event))
+
+		return writeLines(w, lines...)
+	default:
+		return writeLines(w, plainText(event))
+	}
+}
+
+// closeBlocks closes the block of the operation at the given level, along with any blocks nested in it
+// that were left open, and returns the service messages closing them.
+func (t *teamcityDialect) closeBlocks(level int) []string {
+	var lines []string
+
+	for len(t.blocks) > 0 && t.blocks[len(t.blocks)-1].level >= level {
+		lines = append(lines, teamcityMessage("blockClosed", "name", t.blocks[len(t.blocks)-1].name))
+		t.blocks = t.blocks[:len(t.blocks)-1]
+	}
+
+	return lines
+}
+
+// teamcityMessage formats a service message with a single attribute, or a single unnamed value
+// when the attribute has no name.
+func teamcityMessage(name, attribute, value string) string {
+	if attribute == "" {
+		return fmt.Sprintf("##teamcity[%s '%s']", name, teamcityEscaper.Replace(value))
+	}
+
+	return fmt.Sprintf("##teamcity[%s %s='%s']", name, attribute, teamcityEscaper.Replace(value))
+}
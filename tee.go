@@ -0,0 +1,211 @@
+package nesgress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// teeReporter forwards every call to all of its reporters.
+type teeReporter struct {
+	reporters  []ProgressReporter
+	operations releaseStack // releases the contexts handed out by StartWithTimeout
+}
+
+var (
+	_ ProgressReporter = (*teeReporter)(nil)
+	_ TimeoutStarter   = (*teeReporter)(nil)
+	_ Logger           = (*teeReporter)(nil)
+)
+
+// Tee creates a reporter that forwards every call to all the given reporters, in order,
+// e.g. a terminal display together with a log of the same progress.
+// Errors returned by the reporters are combined with errors.Join. Tee panics if any reporter is nil.
+func Tee(reporters ...ProgressReporter) ProgressReporter {
+	for i, reporter := range reporters {
+		if reporter == nil {
+			panic(fmt.Sprintf("nesgress: reporter %d of Tee is nil", i))
+		}
+	}
+
+	return &teeReporter{reporters: reporters}
+}
+
+// forEach calls fn with every reporter and joins the errors they return.
+func (t *teeReporter) forEach(fn func(reporter ProgressReporter) error) error {
+	errs := make([]error, 0, len(t.reporters))
+
+	for _, reporter := range t.reporters {
+		errs = append(errs, fn(reporter))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Start begins a new progress operation on all reporters.
+func (t *teeReporter) Start(message string) error {
+	t.operations.push(nil)
+
+	return t.forEach(func(reporter ProgressReporter) error {
+		return reporter.Start(message)
+	})
+}
+
+// StartWithTimeout begins a new progress operation with a timeout on all reporters supporting timeouts,
+// and a regular operation on the others.
+// The returned context ends as soon as the context of any reporter ends, with the same cause.
+// When no reporter supports timeouts, the context expires once the timeout passes all the same.
+// Either way, it's released once the operation completes.
+func (t *teeReporter) StartWithTimeout(message string, timeout time.Duration) (context.Context, error) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	var stops []func() bool // unregister the reporters' contexts from the returned one
+
+	err := t.forEach(func(reporter ProgressReporter) error {
+		starter, ok := reporter.(TimeoutStarter)
+		if !ok {
+			return reporter.Start(message)
+		}
+
+		reporterCtx, err := starter.StartWithTimeout(message, timeout)
+
+		// Reporters of other packages may hand out no context at all
+		if reporterCtx != nil {
+			stops = append(stops, context.AfterFunc(reporterCtx, func() {
+				cancel(context.Cause(reporterCtx))
+			}))
+		}
+
+		return err
+	})
+
+	cancelTimeout := context.CancelFunc(func() {})
+	if len(stops) == 0 {
+		// Without a reporter enforcing the timeout, the tee enforces it itself
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, timeout, newTimeoutError(timeout))
+	}
+
+	t.operations.push(func() {
+		cancelTimeout()
+		cancel(nil)
+
+		for _, stop := range stops {
+			stop()
+		}
+	})
+
+	return ctx, err
+}
+
+// Update modifies the message of the current progress operation on all reporters.
+func (t *teeReporter) Update(message string) error {
+	return t.forEach(func(reporter ProgressReporter) error {
+		return reporter.Update(message)
+	})
+}
+
+// Finish completes the current progress operation successfully on all reporters.
+func (t *teeReporter) Finish(message string) error {
+	defer t.operations.pop()
+
+	return t.forEach(func(reporter ProgressReporter) error {
+		return reporter.Finish(message)
+	})
+}
+
+// Fail completes the current progress operation with an error on all reporters.
+func (t *teeReporter) Fail(message string, err error) error {
+	defer t.operations.pop()
+
+	return t.forEach(func(reporter ProgressReporter) error {
+		return reporter.Fail(message, err)
+	})
+}
+
+// StartPersistent begins a persistent progress operation on all reporters.
+func (t *teeReporter) StartPersistent(message string) error {
+	t.operations.push(nil)
+
+	return t.forEach(func(reporter ProgressReporter) error {
+		return reporter.StartPersistent(message)
+	})
+}
+
+// LogAccomplishment logs an accomplishment on all reporters.
+func (t *teeReporter) LogAccomplishment(message string) error {
+	return t.forEach(func(reporter ProgressReporter) error {
+		return reporter.LogAccomplishment(message)
+	})
+}
+
+// Log prints an informational line on all reporters.
+func (t *teeReporter) Log(message string) error {
+	return t.forEach(func(reporter ProgressReporter) error {
+		return logLine(reporter, message)
+	})
+}
+
+// FinishPersistent completes persistent progress with success on all reporters.
+func (t *teeReporter) FinishPersistent(message string) error {
+	defer t.operations.pop()
+
+	return t.forEach(func(reporter ProgressReporter) error {
+		return reporter.FinishPersistent(message)
+	})
+}
+
+// FailPersistent completes persistent progress with failure on all reporters.
+func (t *teeReporter) FailPersistent(message string, err error) error {
+	defer t.operations.pop()
+
+	return t.forEach(func(reporter ProgressReporter) error {
+		return reporter.FailPersistent(message, err)
+	})
+}
+
+// Clear stops all progress operations on all reporters.
+func (t *teeReporter) Clear() error {
+	defer t.operations.releaseAll()
+
+	return t.forEach(ProgressReporter.Clear)
+}
+
+// Pause pauses all reporters.
+func (t *teeReporter) Pause() error {
+	return t.forEach(ProgressReporter.Pause)
+}
+
+// Resume resumes all reporters.
+func (t *teeReporter) Resume() error {
+	return t.forEach(ProgressReporter.Resume)
+}
+
+// IsActive returns true if any reporter has active progress operations.
+func (t *teeReporter) IsActive() bool {
+	for _, reporter := range t.reporters {
+		if reporter.IsActive() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPaused returns true if any reporter is paused, since the terminal may then be in use by a prompt.
+func (t *teeReporter) IsPaused() bool {
+	for _, reporter := range t.reporters {
+		if reporter.IsPaused() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Close closes all reporters.
+func (t *teeReporter) Close() error {
+	defer t.operations.releaseAll()
+
+	return t.forEach(ProgressReporter.Close)
+}
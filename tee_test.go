@@ -0,0 +1,186 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+// failingReporter fails every call it receives.
+type failingReporter struct {
+	err error
+
+	nesgress.NoopProgressDisplay
+}
+
+func (r *failingReporter) Start(message string) error {
+	return r.err
+}
+
+func (r *failingReporter) Finish(message string) error {
+	return r.err
+}
+
+func Test_Tee_WithMultipleReporters_ForwardsCallsToEach(t *testing.T) {
+	var buf bytes.Buffer
+
+	display := nesgress.NewProgressDisplay(&buf)
+	recorder := &recordingReporter{}
+	tee := nesgress.Tee(display, recorder)
+
+	require.NoError(t, tee.Start("Building"))
+	require.NoError(t, tee.Update("Building app"))
+	require.True(t, tee.IsActive())
+	require.NoError(t, tee.Finish("Building app"))
+	require.NoError(t, tee.Close())
+
+	require.Equal(t, []string{"start: Building", "update: Building app", "finish: Building app"}, recorder.calls)
+	require.Contains(t, display.GetOutputSafely(), "✓ Building app")
+}
+
+func Test_Tee_WithFailingReporters_JoinsErrorsAndStillCallsOthers(t *testing.T) {
+	errFirst := errors.New("first broke")
+	errSecond := errors.New("second broke")
+	recorder := &recordingReporter{}
+	tee := nesgress.Tee(&failingReporter{err: errFirst}, recorder, &failingReporter{err: errSecond})
+
+	err := tee.Start("Building")
+
+	require.ErrorIs(t, err, errFirst)
+	require.ErrorIs(t, err, errSecond)
+	require.Equal(t, []string{"start: Building"}, recorder.calls)
+}
+
+func Test_Tee_WithoutActiveReporters_IsNotActive(t *testing.T) {
+	tee := nesgress.Tee(nesgress.NewNoopProgressDisplay(), nesgress.NewNoopProgressDisplay())
+
+	require.NoError(t, tee.Start("Building"))
+	require.False(t, tee.IsActive())
+	require.False(t, tee.IsPaused())
+}
+
+func Test_Tee_StartWithTimeout_ExpiresWithFirstReporterContext(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+
+	tee := nesgress.Tee(nesgress.NewProgressDisplay(&buf), nesgress.NewNoopProgressDisplay())
+
+	starter, ok := tee.(nesgress.TimeoutStarter)
+	require.True(t, ok)
+
+	ctx, err := starter.StartWithTimeout("Fetching", 50*time.Millisecond)
+	require.NoError(t, err)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "context did not expire")
+	}
+
+	require.ErrorIs(t, context.Cause(ctx), nesgress.ErrTimeout)
+	require.NoError(t, tee.Close())
+}
+
+func Test_Tee_StartWithTimeout_WhenFinished_ReleasesContext(t *testing.T) {
+	var buf bytes.Buffer
+
+	tee := nesgress.Tee(nesgress.NewProgressDisplay(&buf), nesgress.NewNoopProgressDisplay())
+
+	starter, ok := tee.(nesgress.TimeoutStarter)
+	require.True(t, ok)
+
+	ctx, err := starter.StartWithTimeout("Fetching", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, tee.Finish("Fetching"))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "context was not released")
+	}
+
+	require.NoError(t, tee.Close())
+}
+
+// nilContextReporter hands out no context for operations with a timeout.
+type nilContextReporter struct {
+	nesgress.NoopProgressDisplay
+}
+
+func (r *nilContextReporter) StartWithTimeout(message string, timeout time.Duration) (context.Context, error) {
+	return nil, nil //nolint:nilnil // Mimics a careless reporter of another package
+}
+
+func Test_Tee_StartWithTimeout_WithNilReporterContext_UsesOtherContexts(t *testing.T) {
+	tee := nesgress.Tee(&nilContextReporter{}, nesgress.NewNoopProgressDisplay())
+
+	starter, ok := tee.(nesgress.TimeoutStarter)
+	require.True(t, ok)
+
+	ctx, err := starter.StartWithTimeout("Fetching", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Err())
+
+	require.NoError(t, tee.Finish("Fetching"))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "context was not released")
+	}
+}
+
+// untimedReporter supports no timeouts, hiding those of the reporter it wraps.
+type untimedReporter struct {
+	nesgress.ProgressReporter
+}
+
+func Test_Tee_StartWithTimeout_WithoutReporterSupportingTimeouts_ExpiresAfterTimeout(t *testing.T) {
+	tee := nesgress.Tee(untimedReporter{nesgress.NewNoopProgressDisplay()})
+
+	starter, ok := tee.(nesgress.TimeoutStarter)
+	require.True(t, ok)
+
+	ctx, err := starter.StartWithTimeout("Fetching", 20*time.Millisecond)
+	require.NoError(t, err)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "context did not expire")
+	}
+
+	require.ErrorIs(t, context.Cause(ctx), nesgress.ErrTimeout)
+	require.NoError(t, tee.Finish("Fetching"))
+}
+
+func Test_Tee_StartWithTimeout_WithoutReporterSupportingTimeouts_ReleasesContextOnFinish(t *testing.T) {
+	tee := nesgress.Tee(untimedReporter{nesgress.NewNoopProgressDisplay()})
+
+	starter, ok := tee.(nesgress.TimeoutStarter)
+	require.True(t, ok)
+
+	ctx, err := starter.StartWithTimeout("Fetching", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, tee.Start("Verifying"))
+	require.NoError(t, tee.Finish("Verifying"))
+	require.NoError(t, ctx.Err())
+
+	require.NoError(t, tee.Finish("Fetching"))
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func Test_Tee_WithNilReporter_Panics(t *testing.T) {
+	require.Panics(t, func() {
+		nesgress.Tee(nesgress.NewNoopProgressDisplay(), nil)
+	})
+}
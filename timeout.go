@@ -0,0 +1,43 @@
+package nesgress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is the cause of operations failed automatically by StartWithTimeout.
+var ErrTimeout = errors.New("operation timed out")
+
+// newTimeoutError creates the error a timed out operation fails with.
+func newTimeoutError(timeout time.Duration) error {
+	return fmt.Errorf("%w after %v", ErrTimeout, timeout)
+}
+
+// watchDeadline fails the operation once its context expires, unless it completed first.
+func (p *ProgressDisplay) watchDeadline(ctx context.Context, operation *ProgressOperation) {
+	<-ctx.Done()
+
+	if !errors.Is(context.Cause(ctx), ErrTimeout) {
+		// Released by completion or Clear
+		return
+	}
+
+	// Claim the completion, the caller's Finish or Fail will then only pop the operation
+	if !operation.done.CompareAndSwap(0, 1) {
+		return
+	}
+
+	//nolint:errcheck // Nobody to report display errors to from the watcher
+	_ = p.endOperation(operation, false, context.Cause(ctx))
+
+	// The timed out operation stays on the stack until completed, without being drawn
+	//nolint:errcheck // Nobody to report display errors to from the watcher
+	_ = p.refresh()
+}
+
+// roundUpToSecond rounds a countdown up to whole seconds, so it reaches zero exactly when it expires.
+func roundUpToSecond(remaining time.Duration) time.Duration {
+	return (remaining + time.Second - 1).Truncate(time.Second)
+}
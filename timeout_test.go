@@ -0,0 +1,136 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+func Test_StartWithTimeout_WhenDeadlinePasses_FailsOperationWithTimeoutError(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	ctx, err := display.StartWithTimeout("Waiting for daemon", 50*time.Millisecond)
+	require.NoError(t, err)
+
+	<-ctx.Done()
+	require.ErrorIs(t, context.Cause(ctx), nesgress.ErrTimeout)
+
+	require.Eventually(t, func() bool {
+		return !display.IsActive()
+	}, time.Second, 10*time.Millisecond)
+
+	output := display.GetOutputSafely()
+	require.Contains(t, output, "✗ Waiting for daemon")
+	require.Contains(t, output, "operation timed out after 50ms")
+}
+
+func Test_StartWithTimeout_CompletedAfterTimeout_DoesNotDisplayAgain(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Parent")
+	ctx, _ := display.StartWithTimeout("Waiting for daemon", 20*time.Millisecond)
+	<-ctx.Done()
+
+	require.Eventually(t, func() bool {
+		return bytes.Contains([]byte(display.GetOutputSafely()), []byte("✗"))
+	}, time.Second, 10*time.Millisecond)
+
+	_ = display.Fail("Waiting for daemon", context.Cause(ctx))
+	_ = display.Finish("Parent")
+
+	lines := completionLines(display.GetOutputSafely())
+	require.Equal(t, []string{
+		"✗ Waiting for daemon",
+		"  Error: operation timed out after 20ms",
+		"✓ Parent",
+	}, lines)
+}
+
+func Test_StartWithTimeout_WithUpdatesWhileDeadlinePasses_FailsOperationOnce(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithTreeOutput())
+
+	ctx, err := display.StartWithTimeout("Waiting for daemon", 20*time.Millisecond)
+	require.NoError(t, err)
+
+	// Run with -race to catch the completion reading the message while it's updated
+	for attempt := 1; ctx.Err() == nil; attempt++ {
+		require.NoError(t, display.Update(fmt.Sprintf("Waiting for daemon (attempt %d)", attempt)))
+	}
+
+	require.NoError(t, display.Update("Waiting for daemon (gave up)"))
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(display.GetOutputSafely(), "✗ Waiting for daemon")
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, display.Fail("Daemon unavailable", context.Cause(ctx)))
+	require.NoError(t, display.Close())
+	require.Equal(t, 1, strings.Count(display.GetOutputSafely(), "✗ Waiting for daemon"))
+}
+
+func Test_StartWithTimeout_FinishedBeforeDeadline_SucceedsAndReleasesContext(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	ctx, _ := display.StartWithTimeout("Quick step", time.Minute)
+	_ = display.Finish("Quick step")
+
+	<-ctx.Done()
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	require.NotErrorIs(t, context.Cause(ctx), nesgress.ErrTimeout)
+
+	output := display.GetOutputSafely()
+	require.Contains(t, output, "✓ Quick step")
+	require.NotContains(t, output, "✗")
+}
+
+func Test_StartWithTimeout_WhileRunning_ShowsRemainingTime(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_, _ = display.StartWithTimeout("Waiting for daemon", time.Minute)
+	time.Sleep(100 * time.Millisecond)
+
+	require.Contains(t, display.GetOutputSafely(), "Waiting for daemon (1m0s left)")
+
+	_ = display.Finish("Waiting for daemon")
+}
+
+func Test_NoopProgressDisplay_StartWithTimeout_ReturnsExpiringContext(t *testing.T) {
+	display := nesgress.NewNoopProgressDisplay()
+
+	ctx, err := display.StartWithTimeout("Test", 10*time.Millisecond)
+	require.NoError(t, err)
+
+	<-ctx.Done()
+	require.ErrorIs(t, context.Cause(ctx), nesgress.ErrTimeout)
+	require.False(t, display.IsActive())
+}
+
+func Test_NoopProgressDisplay_StartWithTimeout_WhenFinished_ReleasesContext(t *testing.T) {
+	display := nesgress.NewNoopProgressDisplay()
+
+	require.NoError(t, display.Start("Outer"))
+
+	ctx, err := display.StartWithTimeout("Test", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Err())
+
+	require.NoError(t, display.Finish("Test"))
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	require.NoError(t, display.Finish("Outer"))
+}
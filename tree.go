@@ -0,0 +1,93 @@
+package nesgress
+
+import (
+	"strings"
+)
+
+// settleTree files a completed operation into the tree of the operation enclosing it,
+// and returns the tree that's ready to render, or nil if there is none yet.
+// A tree is ready once its top completed and nothing inside it still runs,
+// so operations outliving a timed out parent still show in the parent's tree.
+func (p *ProgressDisplay) settleTree(operation *ProgressOperation) *ProgressOperation {
+	p.stackMutex.Lock()
+	defer p.stackMutex.Unlock()
+
+	// Operations started inside an already rendered tree form a tree of their own
+	top := operation
+	for top.parent != nil && !top.parent.rendered {
+		top = top.parent
+	}
+
+	if top != operation {
+		// Hold nested completions back until the top of their tree renders it
+		operation.parent.children = append(operation.parent.children, operation)
+	}
+
+	if !top.IsDone() || p.runsInside(top) {
+		return nil
+	}
+
+	top.markRendered()
+
+	return top
+}
+
+// runsInside reports whether any operation nested in the given one is still running.
+// Note: This method assumes the caller holds the stackMutex.
+func (p *ProgressDisplay) runsInside(operation *ProgressOperation) bool {
+	for _, running := range p.progressStack {
+		if running.IsDone() {
+			continue
+		}
+
+		for ancestor := running.parent; ancestor != nil; ancestor = ancestor.parent {
+			if ancestor == operation {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// markRendered marks an operation and the descendants held back in its tree as rendered.
+// Note: This method assumes the caller holds the display's stackMutex.
+func (op *ProgressOperation) markRendered() {
+	op.rendered = true
+
+	for _, child := range op.children {
+		child.markRendered()
+	}
+}
+
+// renderTree renders a completed operation and its held back descendants with tree connectors.
+func (p *ProgressDisplay) renderTree(operation *ProgressOperation) string {
+	var builder strings.Builder
+
+	p.writeTreeNode(&builder, operation, "", "")
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// writeTreeNode writes a single tree node followed by its children.
+// linePrefix precedes the node's own line, childPrefix precedes everything nested under it.
+func (p *ProgressDisplay) writeTreeNode(builder *strings.Builder, operation *ProgressOperation, linePrefix, childPrefix string) {
+	builder.WriteString(linePrefix + p.completionLine(operation.outcome) + "\n")
+
+	if operation.outcome.err != nil {
+		errorPrefix := childPrefix + errorIndent
+		if len(operation.children) > 0 {
+			errorPrefix = childPrefix + strings.TrimSuffix(p.glyphs.trunk, " ")
+		}
+
+		builder.WriteString(p.formatError(operation.outcome.err, errorPrefix) + "\n")
+	}
+
+	for i, child := range operation.children {
+		if i == len(operation.children)-1 {
+			p.writeTreeNode(builder, child, childPrefix+p.glyphs.lastBranch, childPrefix+"   ")
+		} else {
+			p.writeTreeNode(builder, child, childPrefix+p.glyphs.branch, childPrefix+p.glyphs.trunk)
+		}
+	}
+}
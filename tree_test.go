@@ -0,0 +1,128 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+// completionLines returns the lines of output that hold completion messages, without control sequences.
+func completionLines(output string) []string {
+	var lines []string
+
+	for line := range strings.SplitSeq(output, "\n") {
+		// Keep only what follows the last carriage return, which is what the terminal shows
+		if idx := strings.LastIndex(line, "\r"); idx >= 0 {
+			line = line[idx+1:]
+		}
+
+		line = strings.TrimPrefix(line, "\033[K")
+		line = strings.ReplaceAll(line, "\033[?25h", "")
+
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func Test_TreeOutput_WithNestedOperations_PrintsParentAboveChildren(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithTreeOutput())
+
+	_ = display.Start("Parent")
+	_ = display.Start("First child")
+	_ = display.Finish("First child")
+	_ = display.Start("Second child")
+	_ = display.Start("Grandchild")
+	_ = display.Finish("Grandchild")
+	_ = display.Finish("Second child")
+	_ = display.Finish("Parent")
+
+	lines := completionLines(display.GetOutputSafely())
+	require.Equal(t, []string{
+		"✓ Parent",
+		"├─ ✓ First child",
+		"└─ ✓ Second child",
+		"   └─ ✓ Grandchild",
+	}, lines)
+}
+
+func Test_TreeOutput_WithFailedChild_ShowsErrorUnderChild(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithTreeOutput())
+
+	_ = display.Start("Parent")
+	_ = display.Start("Failing child")
+	_ = display.Fail("Failing child", errors.New("child error"))
+	_ = display.Start("Last child")
+	_ = display.Finish("Last child")
+	_ = display.Finish("Parent")
+
+	lines := completionLines(display.GetOutputSafely())
+	require.Equal(t, []string{
+		"✓ Parent",
+		"├─ ✗ Failing child",
+		"│    Error: child error",
+		"└─ ✓ Last child",
+	}, lines)
+}
+
+func Test_TreeOutput_BeforeTopLevelOperationEnds_PrintsNothing(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithTreeOutput())
+
+	_ = display.Start("Parent")
+	_ = display.Start("Child")
+	_ = display.Finish("Child")
+
+	require.NotContains(t, display.GetOutputSafely(), "✓")
+
+	_ = display.Finish("Parent")
+	require.Contains(t, display.GetOutputSafely(), "└─ ✓ Child")
+}
+
+func Test_FlatOutput_ByDefault_PrintsCompletionsWithoutConnectors(t *testing.T) {
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf)
+
+	_ = display.Start("Parent")
+	_ = display.Start("Child")
+	_ = display.Finish("Child")
+	_ = display.Finish("Parent")
+
+	lines := completionLines(display.GetOutputSafely())
+	require.Equal(t, []string{"✓ Child", "✓ Parent"}, lines)
+}
+
+func Test_TreeOutput_WithParentTimingOutBeforeChild_PrintsChildInParentTree(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithTreeOutput())
+
+	ctx, _ := display.StartWithTimeout("Parent", 20*time.Millisecond)
+	_ = display.Start("Child")
+
+	// Give the watcher time to fail the parent while the child still runs
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond)
+
+	_ = display.Finish("Child")
+	_ = display.Fail("Parent", context.Cause(ctx))
+
+	lines := completionLines(display.GetOutputSafely())
+	require.NotEmpty(t, lines)
+	require.True(t, strings.HasPrefix(lines[0], "✗ Parent"), lines[0])
+	require.Equal(t, "└─ ✓ Child", lines[len(lines)-1])
+}
@@ -0,0 +1,100 @@
+package nesgress
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// Separators of the live line.
+const (
+	contextSeparator = ": " // between the messages of an operation and its ancestors
+)
+
+// liveLineMargin is the number of columns of the live line not available to the title:
+// the spinner and the space after it, and the last column, where some terminals wrap right away.
+const liveLineMargin = 3
+
+// titleWidth returns the display width available to the title of the live line, or zero if it's unlimited.
+func (p *ProgressDisplay) titleWidth() int {
+	width := int(p.width.Load())
+	if width <= 0 {
+		return 0
+	}
+
+	return max(width-liveLineMargin, 1)
+}
+
+// terminalRows returns the number of terminal rows the given line takes up once printed,
+// as lines wider than the terminal wrap onto the next rows. Without a known width, every line takes up one row.
+func (p *ProgressDisplay) terminalRows(line string) int {
+	width := int(p.width.Load())
+	lineWidth := ansi.StringWidth(line)
+
+	if width <= 0 || lineWidth <= width {
+		return 1
+	}
+
+	return (lineWidth + width - 1) / width
+}
+
+// measureWidth sets the width of the live line to the width of its terminal, if it follows one.
+func (p *ProgressDisplay) measureWidth() {
+	if p.terminal == nil {
+		return
+	}
+
+	if width, _, err := term.GetSize(p.terminal.Fd()); err == nil {
+		p.width.Store(int64(width))
+	}
+}
+
+// trackWidth makes the live line follow the width of its terminal, if it is one,
+// unless the width was given explicitly.
+func (p *ProgressDisplay) trackWidth() {
+	file, ok := p.rawOutput.(*os.File)
+	if !ok || p.width.Load() != 0 || !isTerminal(file) {
+		return
+	}
+
+	p.terminal = file
+	p.measureWidth()
+}
+
+// watchWidth keeps the width of the live line in sync with its terminal, if it follows one,
+// until the returned function is called. The render loop watches while it runs,
+// so idle displays, and displays never closed, hold no signal handler or goroutine.
+func (p *ProgressDisplay) watchWidth() (stop func()) {
+	if p.terminal == nil {
+		return func() {}
+	}
+
+	// The terminal may have been resized while nothing watched it
+	p.measureWidth()
+
+	return p.watchResize()
+}
+
+// fitTitle joins the parts of a title followed by its live status, fitting them into the given display width.
+// Ancestors between the outermost one and the current operation are left out first, outermost first,
+// and then the end of the title is cut off. A width of zero leaves the title unlimited.
+func (p *ProgressDisplay) fitTitle(parts []string, status string, width int) string {
+	title := strings.Join(parts, contextSeparator) + status
+	if width <= 0 || ansi.StringWidth(title) <= width {
+		return title
+	}
+
+	for elided := 1; elided < len(parts)-1; elided++ {
+		kept := slices.Concat(parts[:1], []string{p.glyphs.ellipsis}, parts[1+elided:])
+
+		title = strings.Join(kept, contextSeparator) + status
+		if ansi.StringWidth(title) <= width {
+			return title
+		}
+	}
+
+	return ansi.Truncate(title, width, p.glyphs.ellipsis)
+}
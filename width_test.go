@@ -0,0 +1,106 @@
+package nesgress_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MrPointer/go-nesgress"
+)
+
+// latestFrame returns the last frame drawn on the live line.
+func latestFrame(output string) string {
+	return strings.TrimSuffix(output[strings.LastIndex(output, "\r")+1:], "\033[K")
+}
+
+// eventuallyDrawn waits until the latest frame of the display contains the given text, and returns it.
+func eventuallyDrawn(t *testing.T, display *nesgress.ProgressDisplay, text string) string {
+	t.Helper()
+
+	var frame string
+
+	require.Eventually(t, func() bool {
+		frame = latestFrame(display.GetOutputSafely())
+		return strings.Contains(frame, text)
+	}, 2*time.Second, 10*time.Millisecond)
+
+	return frame
+}
+
+func Test_Width_WithDeepNesting_ElidesMiddleAncestors(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithWidth(40))
+
+	_ = display.Start("Deploying")
+	_ = display.Start("Building services")
+	_ = display.Start("Building api")
+	_ = display.Start("Compiling x.go")
+
+	frame := eventuallyDrawn(t, display, "Compiling x.go")
+
+	require.Contains(t, frame, "Deploying: …: Compiling x.go")
+	require.LessOrEqual(t, ansi.StringWidth(frame), 40)
+	require.NoError(t, display.Close())
+}
+
+func Test_Width_WithRoomForSomeAncestors_KeepsInnermostOnes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithWidth(50))
+
+	_ = display.Start("Deploying")
+	_ = display.Start("Building services")
+	_ = display.Start("Building api")
+	_ = display.Start("Compiling x.go")
+
+	frame := eventuallyDrawn(t, display, "Compiling x.go")
+
+	require.Contains(t, frame, "Deploying: …: Building api: Compiling x.go")
+	require.NoError(t, display.Close())
+}
+
+func Test_Width_WithWideCharacters_ClipsByDisplayCells(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithWidth(20))
+
+	_ = display.Start("正在安装软件包和依赖项目")
+
+	frame := eventuallyDrawn(t, display, "正在")
+
+	require.LessOrEqual(t, ansi.StringWidth(frame), 20)
+	require.Contains(t, frame, "…")
+	require.NoError(t, display.Close())
+}
+
+func Test_Width_WithShortLine_DrawsItWhole(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	var buf bytes.Buffer
+	display := nesgress.NewProgressDisplay(&buf, nesgress.WithWidth(80))
+
+	_ = display.Start("Deploying")
+	_ = display.Start("Compiling")
+
+	frame := eventuallyDrawn(t, display, "Compiling")
+
+	require.Contains(t, frame, "Deploying: Compiling")
+	require.NotContains(t, frame, "…")
+	require.NoError(t, display.Close())
+}
@@ -3,9 +3,54 @@ package nesgress
 import (
 	"bytes"
 	"io"
+	"reflect"
 	"sync"
 )
 
+// synchronizedWriters hands out a single thread-safe wrapper per underlying writer,
+// so output streams that share a writer also share its lock.
+type synchronizedWriters struct {
+	raw     []io.Writer
+	wrapped []io.Writer
+}
+
+// wrap returns the thread-safe wrapper for the given writer, creating it on first use.
+func (sw *synchronizedWriters) wrap(writer io.Writer) io.Writer {
+	for i, raw := range sw.raw {
+		if sameWriter(raw, writer) {
+			return sw.wrapped[i]
+		}
+	}
+
+	var wrapped io.Writer
+
+	// Special handling for *bytes.Buffer to ensure thread safety of reads as well
+	if buf, ok := writer.(*bytes.Buffer); ok {
+		wrapped = &safeBytesBuffer{buf: buf}
+	} else {
+		wrapped = &synchronizedWriter{writer: writer}
+	}
+
+	sw.raw = append(sw.raw, writer)
+	sw.wrapped = append(sw.wrapped, wrapped)
+
+	return wrapped
+}
+
+// sameWriter reports whether both writers are the same value,
+// without panicking on nil writers or writer types that can't be compared.
+func sameWriter(a, b io.Writer) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+
+	return a == b
+}
+
 // synchronizedWriter wraps an io.Writer with a mutex to prevent concurrent writes.
 type synchronizedWriter struct {
 	writer io.Writer